@@ -0,0 +1,132 @@
+package health
+
+import (
+	"errors"
+	"time"
+)
+
+// BreakerState is the state of the check circuit breaker.
+type BreakerState string
+
+const (
+	// BreakerClosed is the state in which the check is run.
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen is the state in which the check is not run and the latest failure is reported instead.
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen is the state in which a single probe run decides if the breaker is closed or opened again.
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// CircuitBreaker stops running a failing check for a cool-down period, so that an unavailable dependency
+// is not hit by every measurement.
+type CircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failures after which the breaker opens.
+	FailureThreshold int
+	// CoolDown is the period in which the open breaker reports the latest failure without running the check.
+	// After it a single probe run is let through.
+	CoolDown time.Duration
+}
+
+func (b *CircuitBreaker) validate() error {
+	if b.FailureThreshold <= 0 || b.CoolDown <= 0 {
+		return errors.New("health check circuit breaker threshold and cool-down must be positive")
+	}
+
+	return nil
+}
+
+// breaker is the circuit breaker state of a check, it is guarded by the check state mutex.
+type breaker struct {
+	state    BreakerState
+	openedAt time.Time
+	probing  bool
+}
+
+// allow tells if the check can be run according to its circuit breaker. If it cannot,
+// the latest result of the check is returned to be reported instead.
+func (st *checkState) allow(c Config, now time.Time) (ServiceStatus, bool) {
+	if c.CircuitBreaker == nil {
+		return ServiceStatus{}, true
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	switch st.breaker.state {
+	case BreakerOpen:
+		if now.Sub(st.breaker.openedAt) < c.CircuitBreaker.CoolDown {
+			return st.shortCircuit(c), false
+		}
+
+		st.breaker.state = BreakerHalfOpen
+		st.breaker.probing = true
+
+		return ServiceStatus{}, true
+	case BreakerHalfOpen:
+		if st.breaker.probing {
+			return st.shortCircuit(c), false
+		}
+
+		st.breaker.probing = true
+
+		return ServiceStatus{}, true
+	default:
+		return ServiceStatus{}, true
+	}
+}
+
+// shortCircuit returns the latest failure of the check, st.mu must be held. The check is reported as ok
+// as long as the latest failure is, so that the breaker does not bypass the check failure threshold.
+func (st *checkState) shortCircuit(c Config) ServiceStatus {
+	res := ServiceStatus{
+		IsOk:           false,
+		Skippable:      c.SkipOnErr,
+		ShortCircuited: true,
+	}
+
+	if st.last != nil {
+		res.IsOk = st.last.IsOk
+		res.Message = st.last.Message
+		res.TimedOut = st.last.TimedOut
+	}
+
+	return res
+}
+
+// release lets another probe through the half-open circuit breaker if the run allowed by allow is not recorded.
+func (st *checkState) release(c Config) {
+	if c.CircuitBreaker == nil {
+		return
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	st.breaker.probing = false
+}
+
+// trip updates the circuit breaker with the check run, st.mu must be held.
+func (st *checkState) trip(c Config, res *ServiceStatus, finishedAt time.Time) {
+	if c.CircuitBreaker == nil {
+		return
+	}
+
+	if st.breaker.state == "" {
+		st.breaker.state = BreakerClosed
+	}
+
+	// the skipped and the short-circuited runs do not change the breaker state
+	if !res.ShortCircuited && !res.Skipped {
+		switch {
+		case st.failures == 0:
+			st.breaker.state = BreakerClosed
+		case st.breaker.state == BreakerHalfOpen, st.failures >= c.CircuitBreaker.FailureThreshold:
+			st.breaker.state = BreakerOpen
+			st.breaker.openedAt = finishedAt
+		}
+
+		st.breaker.probing = false
+	}
+
+	res.CircuitBreaker = st.breaker.state
+}
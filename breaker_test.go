@@ -0,0 +1,132 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var (
+		calls int32
+		fail  int32 = 1
+	)
+
+	h, err := New(WithChecks(Config{
+		Name:           "mongo",
+		CircuitBreaker: &CircuitBreaker{FailureThreshold: 2, CoolDown: 50 * time.Millisecond},
+		Check: func(context.Context) error {
+			atomic.AddInt32(&calls, 1)
+			if atomic.LoadInt32(&fail) == 1 {
+				return errors.New(checkErr)
+			}
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	measure := func() ServiceStatus {
+		return h.Measure(context.Background()).Services["mongo"]
+	}
+
+	res := measure()
+	assert.Equal(t, BreakerClosed, res.CircuitBreaker)
+
+	res = measure()
+	assert.Equal(t, BreakerOpen, res.CircuitBreaker, "breaker should open on the failure threshold")
+	assert.Equal(t, 2, res.ConsecutiveFailures)
+	assert.False(t, res.ShortCircuited)
+
+	res = measure()
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "open breaker should not run the check")
+	assert.True(t, res.ShortCircuited)
+	assert.False(t, res.IsOk)
+	assert.False(t, res.Failed())
+	assert.Equal(t, checkErr, res.Message)
+	assert.Equal(t, 2, res.ConsecutiveFailures, "short-circuited runs should not count as failures")
+
+	// the half-open probe fails, the breaker is opened again
+	time.Sleep(50 * time.Millisecond)
+	res = measure()
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.Equal(t, BreakerOpen, res.CircuitBreaker)
+	assert.False(t, res.ShortCircuited)
+
+	res = measure()
+	assert.True(t, res.ShortCircuited)
+
+	// the half-open probe succeeds, the breaker is closed
+	atomic.StoreInt32(&fail, 0)
+	time.Sleep(50 * time.Millisecond)
+	res = measure()
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+	assert.Equal(t, BreakerClosed, res.CircuitBreaker)
+	assert.True(t, res.IsOk)
+
+	err = h.Register(Config{
+		Name:           "redis",
+		CircuitBreaker: &CircuitBreaker{FailureThreshold: 1},
+		Check:          func(context.Context) error { return nil },
+	})
+	require.EqualError(t, err, "health check circuit breaker threshold and cool-down must be positive")
+}
+
+func TestCircuitBreakerFailureThreshold(t *testing.T) {
+	var calls int32
+
+	h, err := New(WithChecks(Config{
+		Name:             "mongo",
+		FailureThreshold: 3,
+		CircuitBreaker:   &CircuitBreaker{FailureThreshold: 1, CoolDown: time.Hour},
+		Check: func(context.Context) error {
+			atomic.AddInt32(&calls, 1)
+			return errors.New(checkErr)
+		},
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status, "failure below the threshold should be reported as ok")
+	assert.Equal(t, BreakerOpen, c.Services["mongo"].CircuitBreaker)
+
+	c = h.Measure(context.Background())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.True(t, c.Services["mongo"].ShortCircuited)
+	assert.True(t, c.Services["mongo"].IsOk, "short-circuited run should keep the latest reported state")
+	assert.Equal(t, checkErr, c.Services["mongo"].Message)
+	assert.Equal(t, StatusOK, c.Status)
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	c := Config{Name: "mongo", CircuitBreaker: &CircuitBreaker{FailureThreshold: 1, CoolDown: time.Minute}}
+	st := newCheckState()
+
+	now := time.Now()
+	st.record(c, &ServiceStatus{IsOk: false, Message: checkErr, StartedAt: now})
+
+	_, ok := st.allow(c, now)
+	assert.False(t, ok)
+
+	_, ok = st.allow(c, now.Add(time.Minute))
+	assert.True(t, ok, "first run after the cool-down should be the probe")
+
+	res, ok := st.allow(c, now.Add(time.Minute))
+	assert.False(t, ok, "only a single probe should be let through")
+	assert.True(t, res.ShortCircuited)
+	assert.Equal(t, checkErr, res.Message)
+
+	st.record(c, &res)
+	assert.Equal(t, BreakerHalfOpen, res.CircuitBreaker)
+
+	probe := ServiceStatus{IsOk: true, StartedAt: now.Add(time.Minute)}
+	st.record(c, &probe)
+	assert.Equal(t, BreakerClosed, probe.CircuitBreaker)
+
+	_, ok = st.allow(c, now.Add(time.Minute))
+	assert.True(t, ok)
+}
@@ -0,0 +1,48 @@
+package health
+
+import (
+	"runtime/debug"
+	"time"
+)
+
+// BuildInfo is the build and version information of the service, see WithBuildInfo.
+type BuildInfo struct {
+	// Version is the application version, e.g. the released tag.
+	Version string `json:"version,omitempty"`
+	// Release is the application release identifier, e.g. the deployment ID.
+	Release string `json:"release,omitempty"`
+	// ModuleVersion is the version of the main module of the binary.
+	ModuleVersion string `json:"module_version,omitempty"`
+	// Revision is the VCS revision the binary was built from.
+	Revision string `json:"revision,omitempty"`
+	// RevisionTime is the time of the VCS revision.
+	RevisionTime *time.Time `json:"revision_time,omitempty"`
+	// Dirty tells if the binary was built from a working tree with local modifications.
+	Dirty bool `json:"dirty,omitempty"`
+	// GoVersion is the Go version the binary was built with.
+	GoVersion string `json:"go_version,omitempty"`
+}
+
+// BuildObserver is an Observer notified of the build information of the service, see WithBuildInfo.
+type BuildObserver interface {
+	// ObserveBuild is called once with the build information when the container is created.
+	ObserveBuild(b BuildInfo)
+}
+
+// readBuildInfo fills the fields of the build information that are not set by the application
+// from the build information embedded in the binary.
+func readBuildInfo(b BuildInfo) BuildInfo {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return b
+	}
+
+	// the version of the main module is "(devel)" if the binary is not built from a module version
+	if b.ModuleVersion == "" && bi.Main.Version != "(devel)" {
+		b.ModuleVersion = bi.Main.Version
+	}
+
+	readVCSInfo(bi, &b)
+
+	return b
+}
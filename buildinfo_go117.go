@@ -0,0 +1,16 @@
+//go:build !go1.18
+// +build !go1.18
+
+package health
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// readVCSInfo fills the Go version, the VCS details are not embedded in the binaries built before Go 1.18.
+func readVCSInfo(_ *debug.BuildInfo, b *BuildInfo) {
+	if b.GoVersion == "" {
+		b.GoVersion = runtime.Version()
+	}
+}
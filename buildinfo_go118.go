@@ -0,0 +1,31 @@
+//go:build go1.18
+// +build go1.18
+
+package health
+
+import (
+	"runtime/debug"
+	"time"
+)
+
+// readVCSInfo fills the VCS details and the Go version that are not set from the build information.
+func readVCSInfo(bi *debug.BuildInfo, b *BuildInfo) {
+	if b.GoVersion == "" {
+		b.GoVersion = bi.GoVersion
+	}
+
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if b.Revision == "" {
+				b.Revision = s.Value
+			}
+		case "vcs.time":
+			if t, err := time.Parse(time.RFC3339, s.Value); err == nil && b.RevisionTime == nil {
+				b.RevisionTime = &t
+			}
+		case "vcs.modified":
+			b.Dirty = b.Dirty || s.Value == "true"
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type buildObserver struct {
+	recordingObserver
+	builds []BuildInfo
+}
+
+func (o *buildObserver) ObserveBuild(b BuildInfo) {
+	o.builds = append(o.builds, b)
+}
+
+func TestWithBuildInfo(t *testing.T) {
+	h, err := New()
+	require.NoError(t, err)
+	assert.Nil(t, h.Measure(context.Background()).Build)
+
+	o := &buildObserver{}
+	h, err = New(WithObserver(o), WithBuildInfo(BuildInfo{Version: "1.2.3", Release: "2022-08-01.1", Revision: "abc123"}))
+	require.NoError(t, err)
+
+	b := h.Measure(context.Background()).Build
+	require.NotNil(t, b)
+	assert.Equal(t, "1.2.3", b.Version)
+	assert.Equal(t, "2022-08-01.1", b.Release)
+	assert.Equal(t, "abc123", b.Revision, "application fields should not be overwritten")
+	assert.Equal(t, runtime.Version(), b.GoVersion)
+
+	require.Len(t, o.builds, 1)
+	assert.Equal(t, *b, o.builds[0])
+}
+
+func TestIETFResponseBuildInfo(t *testing.T) {
+	c := Check{Status: StatusOK, Build: &BuildInfo{Version: "1.2.3", Revision: "abc123"}}
+
+	res := NewIETFResponse(c, ServiceInfo{})
+	assert.Equal(t, "1.2.3", res.Version)
+	assert.Equal(t, "abc123", res.ReleaseID)
+
+	c.Build.Release = "2022-08-01.1"
+	res = NewIETFResponse(c, ServiceInfo{})
+	assert.Equal(t, "2022-08-01.1", res.ReleaseID)
+
+	res = NewIETFResponse(c, ServiceInfo{Version: "2", ReleaseID: "2.0.1"})
+	assert.Equal(t, "2", res.Version, "service info should take precedence")
+	assert.Equal(t, "2.0.1", res.ReleaseID)
+}
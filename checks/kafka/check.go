@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/Shopify/sarama"
+	"github.com/mhfinans/health-go"
 	"log"
 	"math/rand"
 	"sync"
@@ -23,6 +24,9 @@ type Config struct {
 	Timeout time.Duration
 	// CustomTopicName optional custom topic name
 	CustomTopicName string
+	// Logger is the logger of the check and of its consumer
+	// If not set - the standard library logger
+	Logger health.Logger
 }
 
 // New creates new Kafka health check that verifies the following:
@@ -36,6 +40,10 @@ func New(config Config) func(ctx context.Context) error {
 		topic = fmt.Sprintf("%s.health-check-topic", config.ServiceName)
 	}
 
+	if config.Logger == nil {
+		config.Logger = health.NewStdLogger(log.Default())
+	}
+
 	groupId := fmt.Sprintf("%s.health-check-consumer-group", config.ServiceName)
 	rand.Seed(time.Now().UnixNano())
 
@@ -48,7 +56,8 @@ func New(config Config) func(ctx context.Context) error {
 
 		version, err := sarama.ParseKafkaVersion(config.Version)
 		if err != nil {
-			log.Fatalln(err)
+			config.Logger.Error("invalid kafka version", health.F("version", config.Version), health.F("error", err.Error()))
+			return fmt.Errorf("invalid kafka version %w", err)
 		}
 
 		kConfig.Version = version
@@ -76,7 +85,7 @@ func New(config Config) func(ctx context.Context) error {
 		defer func(cg sarama.ConsumerGroup) {
 			err := cg.Close()
 			if err != nil {
-				fmt.Println("could not close consumer group:", err)
+				config.Logger.Warn("could not close consumer group", health.F("error", err.Error()))
 			}
 		}(cg)
 
@@ -89,6 +98,7 @@ func New(config Config) func(ctx context.Context) error {
 			setupHooks:          make([]Hook, 0),
 			ready:               make(chan bool),
 			errorChan:           nil,
+			logger:              config.Logger,
 		}
 
 		c.AddClaimer(topic, func(msg *sarama.ConsumerMessage) bool {
@@ -117,7 +127,7 @@ func New(config Config) func(ctx context.Context) error {
 		defer func(p sarama.SyncProducer) {
 			err := p.Close()
 			if err != nil {
-				fmt.Println("could not clean up producer in health check", err)
+				config.Logger.Warn("could not clean up producer in health check", health.F("error", err.Error()))
 			}
 		}(p)
 
@@ -2,9 +2,9 @@ package kafka
 
 import (
 	"context"
+	"fmt"
 	"github.com/Shopify/sarama"
-	"go.uber.org/zap"
-	"log"
+	"github.com/mhfinans/health-go"
 	"sync"
 )
 
@@ -31,6 +31,7 @@ type consumer struct {
 	setupHooks          []Hook
 	ready               chan bool
 	errorChan           chan error
+	logger              health.Logger
 }
 
 type Hook func(session sarama.ConsumerGroupSession)
@@ -49,20 +50,21 @@ func (c *consumer) Listen(parentWg *sync.WaitGroup, close <-chan struct{}) {
 	go c.createConsumerSession(ctx, wg)
 
 	<-c.ready // Wait till the consumer has been set up
-	log.Println("Sarama consumer up and running!...")
+	c.logger.Info("Sarama consumer up and running!...")
 
 	select {
 	case <-ctx.Done():
-		log.Println("terminating: context cancelled")
+		c.logger.Info("terminating: context cancelled")
 	case <-close:
-		log.Println("terminating: close channel signal")
+		c.logger.Info("terminating: close channel signal")
 	}
 
 	cancel()
 	wg.Wait()
 
 	if err := c.consumerGroup.Close(); err != nil {
-		log.Panicf("Error closing kafka consumer: %v", err)
+		c.logger.Error("error closing kafka consumer", health.F("error", err.Error()))
+		panic(fmt.Sprintf("Error closing kafka consumer: %v", err))
 	}
 }
 
@@ -79,7 +81,7 @@ func (c *consumer) AddClaimer(topic string, claimer MessageClaimer) {
 }
 
 func (c *consumer) Setup(session sarama.ConsumerGroupSession) error {
-	log.Println("setting up sarama consumer group...")
+	c.logger.Info("setting up sarama consumer group...")
 	for _, h := range c.setupHooks {
 		h(session)
 	}
@@ -88,7 +90,7 @@ func (c *consumer) Setup(session sarama.ConsumerGroupSession) error {
 }
 
 func (c *consumer) Cleanup(session sarama.ConsumerGroupSession) error {
-	log.Println("cleaning up sarama consumer group...")
+	c.logger.Info("cleaning up sarama consumer group...")
 	for _, h := range c.cleanupHooks {
 		h(session)
 	}
@@ -107,13 +109,13 @@ func (c *consumer) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 				claimer, exists := c.claimers[message.Topic]
 
 				if !exists {
-					log.Println("no handler exists for this topic", message.Topic)
+					c.logger.Warn("no handler exists for this topic", health.F("topic", message.Topic))
 					continue
 				}
 
 				canProcess := claimer(message)
 				if !canProcess {
-					log.Println("could not process message", string(message.Value))
+					c.logger.Warn("could not process message", health.F("message", string(message.Value)))
 					continue
 				}
 
@@ -142,7 +144,8 @@ func (c *consumer) createConsumerSession(ctx context.Context, wg *sync.WaitGroup
 
 	for {
 		if err := c.consumerGroup.Consume(ctx, c.topics, c); err != nil {
-			log.Panicf("error from consumer: %v\n", err)
+			c.logger.Error("error from consumer", health.F("error", err.Error()))
+			panic(fmt.Sprintf("error from consumer: %v", err))
 		}
 
 		if ctx.Err() != nil {
@@ -155,7 +158,7 @@ func (c *consumer) createConsumerSession(ctx context.Context, wg *sync.WaitGroup
 
 func (c *consumer) trackErrors() {
 	for err := range c.consumerGroup.Errors() {
-		log.Println("error in consumer group", zap.Error(err))
+		c.logger.Error("error in consumer group", health.F("error", err.Error()))
 		c.errorChan <- err
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	_ "github.com/lib/pq" // import pg driver
+	"github.com/mhfinans/health-go"
 )
 
 // Config is the PostgreSQL checker configuration settings container.
@@ -19,38 +20,63 @@ type Config struct {
 // - doing the ping command
 // - selecting postgres version
 func New(config Config) func(ctx context.Context) error {
-	return func(ctx context.Context) (checkErr error) {
-		db, err := sql.Open("postgres", config.DSN)
+	return func(ctx context.Context) error {
+		_, err := check(ctx, config)
+		return err
+	}
+}
+
+// NewWithResult creates new PostgreSQL health check that verifies the same as New,
+// and reports the postgres version in the "version" detail of the result.
+func NewWithResult(config Config) health.CheckWithResultFunc {
+	return func(ctx context.Context) (health.Result, error) {
+		version, err := check(ctx, config)
 		if err != nil {
-			checkErr = fmt.Errorf("PostgreSQL health check failed on connect: %w", err)
-			return
+			return health.Result{}, err
 		}
 
-		defer func() {
-			// override checkErr only if there were no other errors
-			if err := db.Close(); err != nil && checkErr == nil {
-				checkErr = fmt.Errorf("PostgreSQL health check failed on connection closing: %w", err)
-			}
-		}()
+		return health.Result{Details: map[string]interface{}{"version": version}}, nil
+	}
+}
 
-		err = db.PingContext(ctx)
-		if err != nil {
-			checkErr = fmt.Errorf("PostgreSQL health check failed on ping: %w", err)
-			return
-		}
+func check(ctx context.Context, config Config) (version string, checkErr error) {
+	db, err := sql.Open("postgres", config.DSN)
+	if err != nil {
+		checkErr = fmt.Errorf("PostgreSQL health check failed on connect: %w", err)
+		return
+	}
 
-		rows, err := db.QueryContext(ctx, `SELECT VERSION()`)
-		if err != nil {
-			checkErr = fmt.Errorf("PostgreSQL health check failed on select: %w", err)
-			return
+	defer func() {
+		// override checkErr only if there were no other errors
+		if err := db.Close(); err != nil && checkErr == nil {
+			checkErr = fmt.Errorf("PostgreSQL health check failed on connection closing: %w", err)
 		}
-		defer func() {
-			// override checkErr only if there were no other errors
-			if err = rows.Close(); err != nil && checkErr == nil {
-				checkErr = fmt.Errorf("PostgreSQL health check failed on rows closing: %w", err)
-			}
-		}()
+	}()
+
+	err = db.PingContext(ctx)
+	if err != nil {
+		checkErr = fmt.Errorf("PostgreSQL health check failed on ping: %w", err)
+		return
+	}
 
+	rows, err := db.QueryContext(ctx, `SELECT VERSION()`)
+	if err != nil {
+		checkErr = fmt.Errorf("PostgreSQL health check failed on select: %w", err)
 		return
 	}
+	defer func() {
+		// override checkErr only if there were no other errors
+		if err = rows.Close(); err != nil && checkErr == nil {
+			checkErr = fmt.Errorf("PostgreSQL health check failed on rows closing: %w", err)
+		}
+	}()
+
+	if rows.Next() {
+		if err = rows.Scan(&version); err != nil {
+			checkErr = fmt.Errorf("PostgreSQL health check failed on scan: %w", err)
+			return
+		}
+	}
+
+	return
 }
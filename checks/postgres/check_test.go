@@ -27,6 +27,21 @@ func TestNew(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestNewWithResult(t *testing.T) {
+	initDB(t)
+
+	check := NewWithResult(Config{
+		DSN: getDSN(t),
+	})
+
+	res, err := check(context.Background())
+	require.NoError(t, err)
+
+	details, ok := res.Details.(map[string]interface{})
+	require.True(t, ok)
+	assert.Contains(t, details["version"], "PostgreSQL")
+}
+
 func TestEnsureConnectionIsClosed(t *testing.T) {
 	initDB(t)
 
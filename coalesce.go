@@ -0,0 +1,129 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// flight is a measurement shared by the concurrent callers measuring the same checks.
+type flight struct {
+	done     chan struct{}
+	services map[string]ServiceStatus
+}
+
+// flights are the in-flight measurements keyed by the names of the measured checks.
+type flights struct {
+	mu sync.Mutex
+	m  map[string]*flight
+}
+
+// coalesce runs the checks, unless the same checks are already being measured, in which case
+// the caller waits for the in-flight measurement instead. The measurement is not cancelled
+// with the context of any caller, it is bounded by the checks timeouts and the measure timeout.
+func (h *Health) coalesce(ctx context.Context, tracer trace.Tracer, checks []registeredCheck) map[string]ServiceStatus {
+	names := make([]string, len(checks))
+	for i, rc := range checks {
+		names[i] = rc.config.Name
+	}
+	key := strings.Join(names, "\x00")
+
+	h.flights.mu.Lock()
+	f, ok := h.flights.m[key]
+	if !ok {
+		if h.flights.m == nil {
+			h.flights.m = make(map[string]*flight)
+		}
+
+		f = &flight{done: make(chan struct{})}
+		h.flights.m[key] = f
+
+		go func() {
+			f.services = h.runChecks(detachedContext{ctx}, tracer, checks)
+			h.observeStatus()
+
+			h.flights.mu.Lock()
+			delete(h.flights.m, key)
+			h.flights.mu.Unlock()
+
+			close(f.done)
+		}()
+	}
+	h.flights.mu.Unlock()
+
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		services := make(map[string]ServiceStatus, len(checks))
+		for _, rc := range checks {
+			services[rc.config.Name] = ServiceStatus{
+				IsOk:        false,
+				Message:     ctx.Err().Error(),
+				Skippable:   rc.config.SkipOnErr,
+				Criticality: rc.config.Criticality,
+			}
+		}
+
+		return services
+	}
+
+	// every caller gets its own copy of the shared results
+	services := make(map[string]ServiceStatus, len(f.services))
+	for name, res := range f.services {
+		services[name] = res
+	}
+
+	return services
+}
+
+// detachedContext keeps the values of the parent context, but not its deadline and cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+// cacheTTL returns the period in which the latest result of the check is reused, see WithCacheTTL.
+func (h *Health) cacheTTL(c Config) time.Duration {
+	if c.CacheTTL > 0 {
+		return c.CacheTTL
+	}
+
+	return h.globalCacheTTL
+}
+
+// cached returns the latest result of the check if it finished within the ttl.
+func (st *checkState) cached(ttl time.Duration, now time.Time) (ServiceStatus, bool) {
+	if ttl <= 0 {
+		return ServiceStatus{}, false
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.last == nil || now.Sub(st.last.StartedAt.Add(st.last.Duration)) >= ttl {
+		return ServiceStatus{}, false
+	}
+
+	res := *st.last
+	res.Cached = true
+
+	return res, true
+}
@@ -0,0 +1,132 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasureCoalescesConcurrentCalls(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+
+	h, err := New(WithChecks(Config{
+		Name: "postgres",
+		Check: func(context.Context) error {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	const callers = 10
+
+	var wg sync.WaitGroup
+	results := make([]Check, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = h.Measure(context.Background())
+		}(i)
+	}
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	// give the callers the time to join the in-flight measurement
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "concurrent callers should share the measurement")
+	for _, c := range results {
+		assert.Equal(t, StatusOK, c.Status)
+	}
+
+	h.Measure(context.Background())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "measurement should not be shared once completed")
+}
+
+func TestMeasureCoalescingCallerCancelled(t *testing.T) {
+	release := make(chan struct{})
+
+	h, err := New(WithChecks(Config{
+		Name: "postgres",
+		Check: func(context.Context) error {
+			<-release
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	shared := make(chan Check)
+	go func() {
+		shared <- h.Measure(context.Background())
+	}()
+
+	cancelled := make(chan Check)
+	go func() {
+		cancelled <- h.Measure(ctx)
+	}()
+
+	cancel()
+	c := <-cancelled
+	assert.Equal(t, StatusUnavailable, c.Status)
+	assert.Equal(t, context.Canceled.Error(), c.Services["postgres"].Message)
+
+	close(release)
+	c = <-shared
+	assert.Equal(t, StatusOK, c.Status, "measurement should not be cancelled by another caller")
+}
+
+func TestCacheTTL(t *testing.T) {
+	var pgCalls, redisCalls int32
+
+	h, err := New(WithCacheTTL(time.Hour), WithChecks(Config{
+		Name: "postgres",
+		Check: func(context.Context) error {
+			atomic.AddInt32(&pgCalls, 1)
+			return nil
+		},
+	}, Config{
+		Name:     "redis",
+		CacheTTL: 20 * time.Millisecond,
+		Check: func(context.Context) error {
+			atomic.AddInt32(&redisCalls, 1)
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	assert.False(t, c.Services["postgres"].Cached)
+
+	c = h.Measure(context.Background())
+	assert.True(t, c.Services["postgres"].Cached)
+	assert.True(t, c.Services["redis"].Cached)
+	assert.True(t, c.Services["redis"].IsOk)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&pgCalls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&redisCalls))
+
+	time.Sleep(20 * time.Millisecond)
+	c = h.Measure(context.Background())
+	assert.False(t, c.Services["redis"].Cached, "check TTL should take precedence")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&pgCalls))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&redisCalls))
+
+	_, err = New(WithCacheTTL(0))
+	require.Error(t, err)
+
+	err = h.Register(Config{Name: "negative", CacheTTL: -time.Second})
+	require.Error(t, err)
+}
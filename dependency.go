@@ -0,0 +1,118 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// dependencyLookup returns the result of a dependency, ok is false if it is not known.
+type dependencyLookup func(name string) (res ServiceStatus, ok bool)
+
+// runDependentCheck runs the check unless one of its dependencies is unavailable.
+func (h *Health) runDependentCheck(ctx context.Context, tracer trace.Tracer, rc registeredCheck, lookup dependencyLookup) ServiceStatus {
+	for _, dep := range rc.config.DependsOn {
+		if res, ok := lookup(dep); ok && !res.IsOk {
+			return h.skipCheck(ctx, tracer, rc.config, rc.state, dep)
+		}
+	}
+
+	return h.runCheck(ctx, tracer, rc.config, rc.state)
+}
+
+// skipCheck records the check as skipped because of the unavailable dependency.
+func (h *Health) skipCheck(ctx context.Context, tracer trace.Tracer, c Config, st *checkState, dep string) ServiceStatus {
+	cs := newCheckSpan(ctx, tracer, c.Name)
+	defer cs.span.End()
+
+	msg := fmt.Sprintf("dependency %s unavailable", dep)
+	cs.span.SetStatus(codes.Error, msg)
+
+	return h.completeCheck(cs, c, st, ServiceStatus{
+		IsOk:      false,
+		Message:   msg,
+		Skippable: c.SkipOnErr,
+		StartedAt: time.Now(),
+		Skipped:   true,
+	})
+}
+
+// dependencyResult returns the latest result of a registered check. The unregistered checks and
+// the checks that have not run yet are not known.
+func (h *Health) dependencyResult(name string) (ServiceStatus, bool) {
+	h.mu.Lock()
+	st, ok := h.states[name]
+	h.mu.Unlock()
+
+	if !ok {
+		return ServiceStatus{}, false
+	}
+
+	return st.lastResult()
+}
+
+// dependents returns the names of the registered checks depending on the check sorted by name, h.mu must be held.
+func (h *Health) dependents(name string) []string {
+	var names []string
+	for _, c := range h.checks {
+		for _, dep := range c.DependsOn {
+			if dep == name {
+				names = append(names, c.Name)
+				break
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// checkCycles returns an error if registering the check creates a dependency cycle, h.mu must be held.
+func (h *Health) checkCycles(c Config) error {
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	state := make(map[string]int, len(h.checks)+1)
+	dependsOn := func(name string) []string {
+		if name == c.Name {
+			return c.DependsOn
+		}
+
+		return h.checks[name].DependsOn
+	}
+
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("health check dependency cycle: %s -> %s", strings.Join(path, " -> "), name)
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+
+		for _, dep := range dependsOn(name) {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = visited
+
+		return nil
+	}
+
+	return visit(c.Name)
+}
@@ -0,0 +1,147 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependentCheckSkipped(t *testing.T) {
+	var calls int32
+
+	h, err := New(WithChecks(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}, Config{
+		Name:      "api",
+		DependsOn: []string{"postgres"},
+		Check: func(context.Context) error {
+			atomic.AddInt32(&calls, 1)
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, c.Status)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls), "dependent check should not run")
+
+	api := c.Services["api"]
+	assert.False(t, api.IsOk)
+	assert.True(t, api.Skipped)
+	assert.False(t, api.Failed())
+	assert.Equal(t, "dependency postgres unavailable", api.Message)
+	assert.Equal(t, 0, api.ConsecutiveFailures, "skipped runs should not count as failures")
+}
+
+func TestDependentCheckRunsAfterDependencies(t *testing.T) {
+	var dbDone, cacheDone int32
+
+	h, err := New(WithChecks(Config{
+		Name: "postgres",
+		Check: func(context.Context) error {
+			time.Sleep(20 * time.Millisecond)
+			atomic.StoreInt32(&dbDone, 1)
+			return nil
+		},
+	}, Config{
+		Name: "redis",
+		Check: func(context.Context) error {
+			time.Sleep(20 * time.Millisecond)
+			atomic.StoreInt32(&cacheDone, 1)
+			return nil
+		},
+	}, Config{
+		Name:      "api",
+		DependsOn: []string{"postgres", "redis"},
+		Check: func(context.Context) error {
+			if atomic.LoadInt32(&dbDone) == 0 || atomic.LoadInt32(&cacheDone) == 0 {
+				return errors.New("dependencies were not measured yet")
+			}
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	startedAt := time.Now()
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status)
+	assert.True(t, c.Services["api"].IsOk, c.Services["api"].Message)
+	assert.Less(t, time.Since(startedAt), 40*time.Millisecond, "independent checks should run in parallel")
+}
+
+func TestDependencyNotMeasured(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "postgres",
+		Tags:  []string{TagLiveness},
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}, Config{
+		Name:      "api",
+		DependsOn: []string{"postgres"},
+		Check:     func(context.Context) error { return nil },
+	}, Config{
+		Name:      "worker",
+		DependsOn: []string{"queue"},
+		Check:     func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	// the dependency has not run yet, the check is run
+	c := h.MeasureTags(context.Background(), TagReadiness)
+	assert.True(t, c.Services["api"].IsOk)
+
+	// the unregistered dependency is not known, the check is run
+	assert.True(t, c.Services["worker"].IsOk)
+	assert.False(t, c.Services["worker"].Skipped)
+
+	// the latest result of the dependency is used
+	h.MeasureTags(context.Background(), TagLiveness)
+	c = h.MeasureTags(context.Background(), TagReadiness)
+	assert.True(t, c.Services["api"].Skipped)
+}
+
+func TestUnregisterDependency(t *testing.T) {
+	check := func(context.Context) error { return nil }
+
+	h, err := New(WithChecks(
+		Config{Name: "postgres", Check: check},
+		Config{Name: "worker", DependsOn: []string{"postgres"}, Check: check},
+		Config{Name: "api", DependsOn: []string{"postgres"}, Check: check},
+	))
+	require.NoError(t, err)
+
+	err = h.Unregister("postgres")
+	require.EqualError(t, err, `health check "postgres" is a dependency of api, worker`)
+	assert.Len(t, h.Checks(), 3, "check should not be unregistered")
+
+	require.NoError(t, h.Unregister("api"))
+	require.NoError(t, h.Unregister("worker"))
+	require.NoError(t, h.Unregister("postgres"))
+}
+
+func TestDependencyCycle(t *testing.T) {
+	check := func(context.Context) error { return nil }
+
+	_, err := New(WithChecks(Config{Name: "a", DependsOn: []string{"a"}, Check: check}))
+	require.EqualError(t, err, `could not register check "a": health check dependency cycle: a -> a`)
+
+	h, err := New(WithChecks(
+		Config{Name: "a", DependsOn: []string{"b"}, Check: check},
+		Config{Name: "b", DependsOn: []string{"c"}, Check: check},
+	))
+	require.NoError(t, err)
+
+	err = h.Register(Config{Name: "c", DependsOn: []string{"a"}, Check: check})
+	require.EqualError(t, err, "health check dependency cycle: c -> a -> b -> c")
+
+	require.NoError(t, h.Register(Config{Name: "c", Check: check}))
+
+	err = h.Replace(Config{Name: "c", DependsOn: []string{"b"}, Check: check})
+	require.EqualError(t, err, "health check dependency cycle: c -> b -> c")
+	assert.Empty(t, h.Checks()[2].DependsOn, "check should not be replaced")
+}
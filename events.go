@@ -0,0 +1,111 @@
+package health
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Event is the status change of a check or of the overall status.
+type Event struct {
+	// Name is the name of the check, it is empty for the overall status.
+	Name string `json:"name,omitempty"`
+	// Previous is the status before the change. The checks and the overall status are initially StatusOK.
+	Previous Status `json:"previous"`
+	// Current is the status after the change.
+	Current Status `json:"current"`
+	// Message is the message of the check result that changed the status.
+	Message string `json:"message,omitempty"`
+	// Timestamp is the time in which the status changed.
+	Timestamp time.Time `json:"timestamp"`
+	// Since is the time in which the previous status started, it is zero if the status has not changed before.
+	Since time.Time `json:"since"`
+}
+
+// events dispatches the status change events to the subscribers.
+type events struct {
+	mu          sync.Mutex
+	nextID      int
+	subscribers map[int]func(Event)
+
+	// statusMu guards the latest overall status and the time in which it changed.
+	statusMu  sync.Mutex
+	status    Status
+	changedAt time.Time
+}
+
+// Subscribe registers the callback that is called with every status change of a check or of the overall status.
+// The callback is called from the goroutines running the checks and must not block.
+// The returned func unsubscribes the callback.
+func (h *Health) Subscribe(fn func(Event)) (unsubscribe func()) {
+	h.events.mu.Lock()
+	defer h.events.mu.Unlock()
+
+	id := h.events.nextID
+	h.events.nextID++
+	h.events.subscribers[id] = fn
+
+	return func() {
+		h.events.mu.Lock()
+		defer h.events.mu.Unlock()
+
+		delete(h.events.subscribers, id)
+	}
+}
+
+// SubscribeChan returns a channel receiving every status change of a check or of the overall status.
+// The events are dropped if the buffer of the channel is full.
+// The returned func unsubscribes and closes the channel.
+func (h *Health) SubscribeChan(buffer int) (<-chan Event, func()) {
+	var (
+		mu     sync.Mutex
+		closed bool
+	)
+
+	ch := make(chan Event, buffer)
+	unsubscribe := h.Subscribe(func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if closed {
+			return
+		}
+
+		select {
+		case ch <- e:
+		default:
+		}
+	})
+
+	return ch, func() {
+		unsubscribe()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !closed {
+			closed = true
+			close(ch)
+		}
+	}
+}
+
+func (e *events) publish(ev Event) {
+	e.mu.Lock()
+	ids := make([]int, 0, len(e.subscribers))
+	for id := range e.subscribers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	subscribers := make([]func(Event), 0, len(ids))
+	for _, id := range ids {
+		subscribers = append(subscribers, e.subscribers[id])
+	}
+	e.mu.Unlock()
+
+	// the callbacks are called without the lock, so that they can unsubscribe
+	for _, fn := range subscribers {
+		fn(ev)
+	}
+}
@@ -0,0 +1,100 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribe(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		fail   bool
+		events []Event
+	)
+
+	h, err := New(WithChecks(Config{
+		Name: "postgres",
+		Check: func(context.Context) error {
+			if fail {
+				return errors.New(checkErr)
+			}
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	unsubscribe := h.Subscribe(func(e Event) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		events = append(events, e)
+	})
+
+	h.Measure(context.Background())
+	assert.Empty(t, events, "no event should be emitted while the status does not change")
+
+	fail = true
+	h.Measure(context.Background())
+	h.Measure(context.Background())
+
+	require.Len(t, events, 2)
+	assert.Equal(t, "postgres", events[0].Name)
+	assert.Equal(t, StatusOK, events[0].Previous)
+	assert.Equal(t, StatusUnavailable, events[0].Current)
+	assert.Equal(t, checkErr, events[0].Message)
+	assert.False(t, events[0].Timestamp.IsZero())
+	assert.True(t, events[0].Since.IsZero())
+
+	assert.Equal(t, "", events[1].Name, "overall status change should have no name")
+	assert.Equal(t, StatusOK, events[1].Previous)
+	assert.Equal(t, StatusUnavailable, events[1].Current)
+
+	fail = false
+	h.Measure(context.Background())
+
+	require.Len(t, events, 4)
+	assert.Equal(t, StatusUnavailable, events[2].Previous)
+	assert.Equal(t, StatusOK, events[2].Current)
+	assert.Equal(t, events[0].Timestamp, events[2].Since)
+	assert.Equal(t, StatusOK, events[3].Current)
+
+	unsubscribe()
+
+	fail = true
+	h.Measure(context.Background())
+	assert.Len(t, events, 4, "no event should be received after unsubscribing")
+}
+
+func TestSubscribeChan(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:      "rabbitmq",
+		SkipOnErr: true,
+		Check:     func(context.Context) error { return errors.New(checkErr) },
+	}))
+	require.NoError(t, err)
+
+	ch, unsubscribe := h.SubscribeChan(1)
+
+	h.Measure(context.Background())
+
+	e := <-ch
+	assert.Equal(t, "rabbitmq", e.Name)
+	assert.Equal(t, StatusPartiallyAvailable, e.Current)
+
+	select {
+	case e := <-ch:
+		t.Fatalf("overall status event should be dropped as the buffer is full, got %v", e)
+	default:
+	}
+
+	unsubscribe()
+	unsubscribe()
+
+	_, ok := <-ch
+	assert.False(t, ok, "channel should be closed after unsubscribing")
+}
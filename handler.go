@@ -0,0 +1,206 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// SkippablePolicy defines how the failures of the skippable checks affect the HTTP response code.
+type SkippablePolicy int
+
+const (
+	// SkippableDegrade responds with the code of StatusPartiallyAvailable, it is the default policy.
+	SkippableDegrade SkippablePolicy = iota
+	// SkippableIgnore responds with the code of StatusOK, as if the skippable checks had passed.
+	SkippableIgnore
+	// SkippableFail responds with the code of StatusUnavailable, as if the skippable checks were critical.
+	SkippableFail
+)
+
+// HandlerOption is the HTTP handlers options type.
+type HandlerOption func(*handlerConfig)
+
+type handlerConfig struct {
+	statusCodes map[Status]int
+	skippable   SkippablePolicy
+	headers     []responseHeader
+}
+
+type responseHeader struct {
+	key      string
+	value    string
+	statuses []Status
+}
+
+func newHandlerConfig(opts []HandlerOption) handlerConfig {
+	var hc handlerConfig
+	for _, o := range opts {
+		o(&hc)
+	}
+
+	return hc
+}
+
+// WithHTTPStatus sets the HTTP response code of the handler for the status,
+// it takes precedence over the code set for the container with WithStatusCode.
+// The codes out of the 100-599 range are ignored.
+func WithHTTPStatus(s Status, code int) HandlerOption {
+	return func(hc *handlerConfig) {
+		if code < 100 || code > 599 {
+			return
+		}
+
+		if hc.statusCodes == nil {
+			hc.statusCodes = make(map[Status]int)
+		}
+
+		hc.statusCodes[s] = code
+	}
+}
+
+// WithSkippablePolicy sets how the failures of the skippable checks affect the HTTP response code of the handler.
+// The status in the response body is not changed.
+func WithSkippablePolicy(p SkippablePolicy) HandlerOption {
+	return func(hc *handlerConfig) {
+		hc.skippable = p
+	}
+}
+
+// WithHeader adds the header to the handler responses with any of the statuses,
+// or to all the responses if no status is given, e.g. Retry-After for StatusUnavailable.
+func WithHeader(key, value string, statuses ...Status) HandlerOption {
+	return func(hc *handlerConfig) {
+		hc.headers = append(hc.headers, responseHeader{key: key, value: value, statuses: statuses})
+	}
+}
+
+// LivenessHandler returns a liveness HTTP handler (http.HandlerFunc).
+func (h *Health) LivenessHandler(opts ...HandlerOption) http.Handler {
+	hc := newHandlerConfig(opts)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.writeLiveness(w, r, hc)
+	})
+}
+
+// LivenessHandlerFunc is the liveness HTTP handler function, it evaluates the checks tagged with TagLiveness.
+// If there are no liveness checks, the service is always reported as ok.
+func (h *Health) LivenessHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	h.writeLiveness(w, r, handlerConfig{})
+}
+
+func (h *Health) writeLiveness(w http.ResponseWriter, r *http.Request, hc handlerConfig) {
+	c := h.MeasureTags(r.Context(), TagLiveness)
+
+	l := Liveness{IsOK: c.IsOK}
+	if len(c.Services) > 0 {
+		l.Status = c.Status
+		l.Services = c.Services
+	}
+
+	h.writeJSON(w, contentTypeJSON, l, c.Status, hc)
+}
+
+// ReadinessHandler returns an readiness HTTP handler (http.HandlerFunc).
+func (h *Health) ReadinessHandler(opts ...HandlerOption) http.Handler {
+	hc := newHandlerConfig(opts)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := h.MeasureTags(r.Context(), TagReadiness)
+
+		h.writeCheck(w, r, c, hc)
+	})
+}
+
+// ReadinessHandlerFunc is the readiness HTTP handler function, it evaluates the checks tagged with TagReadiness.
+func (h *Health) ReadinessHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	c := h.MeasureTags(r.Context(), TagReadiness)
+
+	h.writeCheck(w, r, c, handlerConfig{})
+}
+
+// TagsHandler returns an HTTP handler that evaluates only the checks tagged with any of the tags.
+func (h *Health) TagsHandler(tags ...string) http.Handler {
+	return h.TagsHandlerWithOptions(tags)
+}
+
+// TagsHandlerWithOptions is the same as TagsHandler, with the handler options.
+func (h *Health) TagsHandlerWithOptions(tags []string, opts ...HandlerOption) http.Handler {
+	hc := newHandlerConfig(opts)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := h.MeasureTags(r.Context(), tags...)
+
+		h.writeCheck(w, r, c, hc)
+	})
+}
+
+// writeCheck writes the check response in the IETF format if it is configured or accepted by the client.
+func (h *Health) writeCheck(w http.ResponseWriter, r *http.Request, c Check, hc handlerConfig) {
+	if h.ietfFormat || acceptsIETF(r) {
+		h.writeJSON(w, ContentTypeHealthJSON, NewIETFResponse(c, h.serviceInfo), c.Status, hc)
+		return
+	}
+
+	h.writeJSON(w, contentTypeJSON, c, c.Status, hc)
+}
+
+func (h *Health) writeJSON(w http.ResponseWriter, contentType string, v interface{}, status Status, hc handlerConfig) {
+	w.Header().Set("Content-Type", contentType)
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	for _, header := range hc.headers {
+		if header.matches(status) {
+			w.Header().Set(header.key, header.value)
+		}
+	}
+
+	w.WriteHeader(h.statusCode(status, hc))
+	w.Write(data)
+}
+
+func (rh responseHeader) matches(s Status) bool {
+	if len(rh.statuses) == 0 {
+		return true
+	}
+
+	for _, status := range rh.statuses {
+		if status == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// statusCode returns the HTTP response code for the status, see WithHTTPStatus and WithStatusCode.
+func (h *Health) statusCode(s Status, hc handlerConfig) int {
+	if s == StatusPartiallyAvailable {
+		switch hc.skippable {
+		case SkippableIgnore:
+			s = StatusOK
+		case SkippableFail:
+			s = StatusUnavailable
+		}
+	}
+
+	if code, ok := hc.statusCodes[s]; ok {
+		return code
+	}
+
+	if code, ok := h.statusCodes[s]; ok {
+		return code
+	}
+
+	if s == StatusUnavailable {
+		return http.StatusInternalServerError
+	}
+
+	return http.StatusOK
+}
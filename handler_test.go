@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerOptions(t *testing.T) {
+	h, err := New(
+		WithStatusCode(StatusUnavailable, http.StatusServiceUnavailable),
+		WithChecks(Config{
+			Name:      "rabbitmq",
+			SkipOnErr: true,
+			Check:     func(context.Context) error { return errors.New(checkErr) },
+		}),
+	)
+	require.NoError(t, err)
+
+	serve := func(handler http.Handler) *httptest.ResponseRecorder {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/status", nil))
+		return res
+	}
+
+	res := serve(h.ReadinessHandler())
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Empty(t, res.Header().Get("Retry-After"))
+
+	res = serve(h.ReadinessHandler(WithHTTPStatus(StatusPartiallyAvailable, http.StatusTooManyRequests)))
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+
+	res = serve(h.ReadinessHandler(
+		WithSkippablePolicy(SkippableFail),
+		WithHeader("Retry-After", "30", StatusUnavailable, StatusPartiallyAvailable),
+		WithHeader("Cache-Control", "no-store"),
+	))
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code, "container status code should be used")
+	assert.Equal(t, "30", res.Header().Get("Retry-After"))
+	assert.Equal(t, "no-store", res.Header().Get("Cache-Control"))
+
+	res = serve(h.TagsHandlerWithOptions([]string{TagReadiness},
+		WithSkippablePolicy(SkippableFail),
+		WithHTTPStatus(StatusUnavailable, http.StatusBadGateway),
+	))
+	assert.Equal(t, http.StatusBadGateway, res.Code, "handler status code should take precedence")
+
+	res = serve(h.ReadinessHandler(WithSkippablePolicy(SkippableFail), WithHTTPStatus(StatusUnavailable, 0)))
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code, "invalid handler status code should be ignored")
+
+	require.NoError(t, h.Replace(Config{
+		Name:  "rabbitmq",
+		Check: func(context.Context) error { return nil },
+	}))
+
+	res = serve(h.ReadinessHandler(WithHeader("Retry-After", "30", StatusUnavailable)))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Empty(t, res.Header().Get("Retry-After"))
+}
+
+func TestSkippableIgnorePolicy(t *testing.T) {
+	h, err := New(
+		WithStatusCode(StatusPartiallyAvailable, http.StatusMultiStatus),
+		WithChecks(Config{
+			Name:        "cache",
+			Criticality: CriticalityDegraded,
+			Tags:        []string{TagLiveness},
+			Check:       func(context.Context) error { return errors.New(checkErr) },
+		}),
+	)
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	h.LivenessHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/liveness", nil))
+	assert.Equal(t, http.StatusMultiStatus, res.Code)
+
+	res = httptest.NewRecorder()
+	h.LivenessHandler(WithSkippablePolicy(SkippableIgnore)).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/liveness", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+}
@@ -2,11 +2,10 @@ package health
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
-	"runtime"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -28,6 +27,28 @@ const (
 	StatusTimeout            Status = "Timeout during health check"
 )
 
+// Well-known check tags, see Config.Tags.
+const (
+	// TagLiveness marks the checks evaluated by the liveness handler.
+	TagLiveness = "liveness"
+	// TagReadiness marks the checks evaluated by the readiness handler.
+	TagReadiness = "readiness"
+	// TagStartup marks the checks evaluated by the startup handler.
+	TagStartup = "startup"
+)
+
+// Criticality is the impact of a failed check on the overall status.
+type Criticality string
+
+const (
+	// CriticalityCritical checks make the status unavailable when they fail.
+	CriticalityCritical Criticality = "critical"
+	// CriticalityDegraded checks make the status partially available when they fail.
+	CriticalityDegraded Criticality = "degraded"
+	// CriticalityInformational checks are reported along with their messages, but do not affect the status.
+	CriticalityInformational Criticality = "informational"
+)
+
 type (
 	// CheckFunc is the func which executes the check.
 	CheckFunc func(context.Context) error
@@ -39,15 +60,84 @@ type (
 		// Timeout is the timeout defined for every check.
 		Timeout time.Duration
 		// SkipOnErr if set to true, it will retrieve StatusOK providing the error message from the failed resource.
+		// It is the same as CriticalityDegraded, and it is ignored if Criticality is set.
 		SkipOnErr bool
-		// Check is the func which executes the check.
+		// Criticality is the impact of the check failure on the overall status.
+		// If not set, the check is critical, or degraded if SkipOnErr is set.
+		Criticality Criticality
+		// Check is the func which executes the check. Either Check or CheckWithResult is required.
 		Check CheckFunc
+		// CheckWithResult is the func which executes the check and returns its structured result,
+		// it is used instead of Check if set.
+		CheckWithResult CheckWithResultFunc
+		// Interval is the period between two runs of the check in background mode, see WithBackgroundInterval.
+		// If not set, the interval of the container is used.
+		Interval time.Duration
+		// FailureThreshold is the number of consecutive failures after which the check is reported as failed.
+		// If not set, the check is reported as failed on the first failure.
+		FailureThreshold int
+		// SuccessThreshold is the number of consecutive successes after which a failed check is reported as ok again.
+		// If not set, the check is reported as ok on the first success.
+		SuccessThreshold int
+		// Tags are the groups the check belongs to, see MeasureTags and TagsHandler.
+		// If not set, the check is a readiness check.
+		Tags []string
+		// DependsOn are the names of the checks this check depends on. The check runs after its dependencies
+		// and is skipped if any of them is unavailable.
+		DependsOn []string
+		// Retry is the policy to retry the failed check within a single run. If not set, the check is not retried.
+		Retry *RetryPolicy
+		// CircuitBreaker stops running the check for a cool-down period after consecutive failures.
+		// If not set, the check is always run.
+		CircuitBreaker *CircuitBreaker
+		// CacheTTL is the period in which the latest result of the check is reused by Measure instead of running
+		// the check again. If not set, the TTL of the container is used, see WithCacheTTL.
+		CacheTTL time.Duration
 	}
 
 	ServiceStatus struct {
 		IsOk      bool   `json:"is_ok"`
 		Message   string `json:"message"`
 		Skippable bool   `json:"skippable"`
+		// Criticality is the impact of the check failure on the overall status.
+		Criticality Criticality `json:"criticality,omitempty"`
+		// Duration is the time the check took to complete or to time out.
+		Duration time.Duration `json:"duration"`
+		// StartedAt is the time in which the check started.
+		StartedAt time.Time `json:"started_at"`
+		// LastSuccess is the time in which the check succeeded the last time.
+		LastSuccess *time.Time `json:"last_success,omitempty"`
+		// LastFailure is the time in which the check failed the last time.
+		LastFailure *time.Time `json:"last_failure,omitempty"`
+		// ConsecutiveFailures is the number of the check failures in a row, including the current one.
+		ConsecutiveFailures int `json:"consecutive_failures"`
+		// ConsecutiveSuccesses is the number of the check successes in a row, including the current one.
+		ConsecutiveSuccesses int `json:"consecutive_successes"`
+		// TimedOut tells if the check did not complete within its timeout.
+		TimedOut bool `json:"timed_out,omitempty"`
+		// Skipped tells if the check was not run because one of its dependencies is unavailable.
+		Skipped bool `json:"skipped,omitempty"`
+		// Panicked tells if the check panicked, the message holds the panic value.
+		Panicked bool `json:"panicked,omitempty"`
+		// Stack is the trimmed stack trace of the panicking check.
+		Stack string `json:"stack,omitempty"`
+		// Attempts is the number of the check attempts made in the run, see Config.Retry.
+		Attempts int `json:"attempts,omitempty"`
+		// CircuitBreaker is the state of the check circuit breaker, it is set only if the breaker is configured.
+		CircuitBreaker BreakerState `json:"circuit_breaker,omitempty"`
+		// ShortCircuited tells if the check was not run because its circuit breaker is open,
+		// the latest failure is reported instead.
+		ShortCircuited bool `json:"short_circuited,omitempty"`
+		// Cached tells if the result is reused from a previous run, see Config.CacheTTL.
+		Cached bool `json:"cached,omitempty"`
+		// Details are the check details, see Config.CheckWithResult.
+		Details interface{} `json:"details,omitempty"`
+		// ObservedValue is the value measured by the check, see Config.CheckWithResult.
+		ObservedValue interface{} `json:"observed_value,omitempty"`
+		// ObservedUnit is the unit of the observed value.
+		ObservedUnit string `json:"observed_unit,omitempty"`
+		// Warning is the warning message of a check that passed in a degraded state.
+		Warning string `json:"warning,omitempty"`
 	}
 
 	// Check represents the health check response.
@@ -62,6 +152,8 @@ type (
 		Services map[string]ServiceStatus `json:"service"`
 		// System holds information of the go process.
 		System `json:"system"`
+		// Build is the build information of the service, it is set only with WithBuildInfo.
+		Build *BuildInfo `json:"build,omitempty"`
 	}
 
 	// System runtime variables about the go process.
@@ -76,33 +168,78 @@ type (
 		HeapObjectsCount int `json:"heap_objects_count"`
 		// TotalAllocBytes is the bytes allocated and not yet freed.
 		AllocBytes int `json:"alloc_bytes"`
+		// GC are the garbage collector statistics, see SystemGC.
+		GC *GCStats `json:"gc,omitempty"`
+		// Memory are the memory statistics, see SystemMemory.
+		Memory *MemoryStats `json:"memory,omitempty"`
+		// CPU are the CPU statistics, see SystemCPU.
+		CPU *CPUStats `json:"cpu,omitempty"`
+		// FileDescriptors are the file descriptors statistics, see SystemFileDescriptors.
+		// They are not set if they are not known on the platform.
+		FileDescriptors *FileDescriptorStats `json:"file_descriptors,omitempty"`
+		// Process are the process details, see SystemProcess.
+		Process *ProcessStats `json:"process,omitempty"`
 	}
 
+	// Liveness represents the liveness check response.
 	Liveness struct {
+		// IsOK tells if all non-skippable liveness checks are ok.
 		IsOK IsOK `json:"is_service_ok"`
+		// Status is the liveness checks status, it is set only if there are liveness checks registered.
+		Status Status `json:"status,omitempty"`
+		// Services holds the liveness checks along with their messages.
+		Services map[string]ServiceStatus `json:"service,omitempty"`
 	}
 
 	// Health is the health-checks container
 	Health struct {
 		mu     sync.Mutex
 		checks map[string]Config
+		states map[string]*checkState
+		infos  map[string]InfoConfig
 
 		tp                  trace.TracerProvider
 		instrumentationName string
-	}
 
-	checkResponse struct {
-		name      string
-		skipOnErr bool
-		err       error
+		measureTimeout time.Duration
+		globalCacheTTL time.Duration
+		flights        flights
+
+		stalledMu sync.Mutex
+		stalled   map[string]int
+
+		scheduler *scheduler
+		startup   startup
+
+		ietfFormat    bool
+		serviceInfo   ServiceInfo
+		statusCodes   map[Status]int
+		systemMetrics SystemMetrics
+		build         *BuildInfo
+
+		observers []Observer
+		events    events
+		logger    Logger
 	}
 )
 
+// Failed tells if the check run failed, regardless of the check thresholds.
+// The skipped and the short-circuited runs are not failed.
+func (s ServiceStatus) Failed() bool {
+	return !s.Skipped && !s.ShortCircuited && s.ConsecutiveFailures > 0
+}
+
 // New instantiates and build new health check container
 func New(opts ...Option) (*Health, error) {
 	h := &Health{
-		checks: make(map[string]Config),
-		tp:     trace.NewNoopTracerProvider(),
+		checks:  make(map[string]Config),
+		states:  make(map[string]*checkState),
+		infos:   make(map[string]InfoConfig),
+		stalled: make(map[string]int),
+		startup: startup{tasks: make(map[string]bool)},
+		events:  events{subscribers: make(map[int]func(Event)), status: StatusOK},
+		tp:      trace.NewNoopTracerProvider(),
+		logger:  NewNopLogger(),
 	}
 
 	for _, o := range opts {
@@ -111,17 +248,32 @@ func New(opts ...Option) (*Health, error) {
 		}
 	}
 
+	if s := h.scheduler; s != nil {
+		if s.interval == 0 {
+			return nil, errors.New("health checks background jitter is set without background interval")
+		}
+
+		if s.jitter < 0 {
+			s.jitter = s.interval / 10
+		}
+	}
+
+	if h.build != nil {
+		for _, o := range h.observers {
+			if bo, ok := o.(BuildObserver); ok {
+				bo.ObserveBuild(*h.build)
+			}
+		}
+	}
+
 	return h, nil
 }
 
 // Register registers a check config to be performed.
 func (h *Health) Register(c Config) error {
-	if c.Timeout == 0 {
-		c.Timeout = time.Second * 2
-	}
-
-	if c.Name == "" {
-		return errors.New("health check must have a name to be registered")
+	c, err := prepareConfig(c)
+	if err != nil {
+		return err
 	}
 
 	h.mu.Lock()
@@ -131,56 +283,177 @@ func (h *Health) Register(c Config) error {
 		return fmt.Errorf("health check %q is already registered", c.Name)
 	}
 
+	if err := h.checkCycles(c); err != nil {
+		return err
+	}
+
 	h.checks[c.Name] = c
+	h.states[c.Name] = newCheckState()
+	h.scheduler.schedule(h, c, h.states[c.Name])
 
 	return nil
 }
 
-func (h *Health) LivenessHandler() http.Handler {
-	return http.HandlerFunc(h.LivenessHandlerFunc)
-}
+// Unregister removes a registered check, it is safe to call while the checks are being measured.
+// A check that other checks depend on cannot be removed.
+func (h *Health) Unregister(name string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 
-func (h *Health) LivenessHandlerFunc(w http.ResponseWriter, r *http.Request) {
-	c := Liveness{IsOK: true}
-	data, err := json.Marshal(c)
+	if _, ok := h.checks[name]; !ok {
+		return fmt.Errorf("health check %q is not registered", name)
+	}
 
-	w.Header().Add("Content-Type", "application/json")
+	if deps := h.dependents(name); len(deps) > 0 {
+		return fmt.Errorf("health check %q is a dependency of %s", name, strings.Join(deps, ", "))
+	}
 
+	delete(h.checks, name)
+	delete(h.states, name)
+	h.scheduler.unschedule(name)
+	h.forgetCheck(name)
+
+	return nil
+}
+
+// Replace replaces the config of a registered check with the same name. The state of the check,
+// like the consecutive failures, is reset. It is safe to call while the checks are being measured.
+func (h *Health) Replace(c Config) error {
+	c, err := prepareConfig(c)
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.checks[c.Name]; !ok {
+		return fmt.Errorf("health check %q is not registered", c.Name)
+	}
+
+	if err := h.checkCycles(c); err != nil {
+		return err
 	}
-	w.WriteHeader(http.StatusOK)
-	w.Write(data)
+
+	h.scheduler.unschedule(c.Name)
+	h.forgetCheck(c.Name)
+
+	h.checks[c.Name] = c
+	h.states[c.Name] = newCheckState()
+	h.scheduler.schedule(h, c, h.states[c.Name])
+
+	return nil
 }
 
-// ReadinessHandler returns an readiness HTTP handler (http.HandlerFunc).
-func (h *Health) ReadinessHandler() http.Handler {
-	return http.HandlerFunc(h.ReadinessHandlerFunc)
+// Checks returns the configs of the registered checks sorted by name.
+func (h *Health) Checks() []Config {
+	checks := h.registered()
+
+	configs := make([]Config, 0, len(checks))
+	for _, rc := range checks {
+		configs = append(configs, rc.config)
+	}
+
+	return configs
 }
 
-// ReadinessHandlerFunc is the readiness HTTP handler function.
-func (h *Health) ReadinessHandlerFunc(w http.ResponseWriter, r *http.Request) {
-	c := h.Measure(r.Context())
+// registeredCheck is a registered check config along with its state.
+type registeredCheck struct {
+	config Config
+	state  *checkState
+}
 
-	w.Header().Set("Content-Type", "application/json")
+// registered returns the registered checks sorted by name.
+func (h *Health) registered() []registeredCheck {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 
-	data, err := json.Marshal(c)
-	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	checks := make([]registeredCheck, 0, len(h.checks))
+	for name, c := range h.checks {
+		checks = append(checks, registeredCheck{config: c, state: h.states[name]})
+	}
+
+	sort.Slice(checks, func(i, j int) bool {
+		return checks[i].config.Name < checks[j].config.Name
+	})
+
+	return checks
+}
+
+// hasAnyTag tells if the check is tagged with any of the tags, untagged checks are readiness checks.
+func (c Config) hasAnyTag(tags []string) bool {
+	checkTags := c.Tags
+	if len(checkTags) == 0 {
+		checkTags = []string{TagReadiness}
+	}
+
+	for _, tag := range tags {
+		for _, checkTag := range checkTags {
+			if tag == checkTag {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func prepareConfig(c Config) (Config, error) {
+	if c.Timeout == 0 {
+		c.Timeout = time.Second * 2
+	}
+
+	if c.FailureThreshold == 0 {
+		c.FailureThreshold = 1
+	}
+
+	if c.SuccessThreshold == 0 {
+		c.SuccessThreshold = 1
+	}
+
+	if c.Name == "" {
+		return c, errors.New("health check must have a name to be registered")
+	}
+
+	if c.Check == nil && c.CheckWithResult == nil {
+		return c, errors.New("health check must have a check func to be registered")
+	}
+
+	if c.FailureThreshold < 0 || c.SuccessThreshold < 0 {
+		return c, errors.New("health check thresholds must not be negative")
+	}
+
+	switch c.Criticality {
+	case "":
+		c.Criticality = CriticalityCritical
+		if c.SkipOnErr {
+			c.Criticality = CriticalityDegraded
+		}
+	case CriticalityCritical, CriticalityDegraded, CriticalityInformational:
+	default:
+		return c, fmt.Errorf("health check criticality %q is not supported", c.Criticality)
+	}
+
+	if c.Retry != nil {
+		if err := c.Retry.validate(); err != nil {
+			return c, err
+		}
+	}
+
+	if c.CircuitBreaker != nil {
+		if err := c.CircuitBreaker.validate(); err != nil {
+			return c, err
+		}
 	}
 
-	code := http.StatusOK
-	if c.Status == StatusUnavailable {
-		code = http.StatusInternalServerError
+	if c.CacheTTL < 0 {
+		return c, errors.New("health check cache TTL must not be negative")
 	}
 
-	w.Header().Add("Content-Type", "application/json")
-	w.WriteHeader(code)
-	w.Write(data)
+	// only the critical checks failures make the service unavailable
+	c.SkipOnErr = c.Criticality != CriticalityCritical
+
+	return c, nil
 }
 
 type checkSpan struct {
@@ -190,118 +463,336 @@ type checkSpan struct {
 
 func newCheckSpan(ctx context.Context, tracer trace.Tracer, name string) checkSpan {
 	var cs checkSpan
-	cs.ctx, cs.span = tracer.Start(ctx, name)
+	cs.ctx, cs.span = tracer.Start(ctx, name, trace.WithAttributes(checkKey.String(name)))
 	return cs
 }
 
-// Measure runs all the registered health checks and returns summary status
+func (cs checkSpan) setAttributes(res ServiceStatus) {
+	cs.span.SetAttributes(
+		attribute.Float64("duration_ms", float64(res.Duration)/float64(time.Millisecond)),
+		attribute.String("started_at", res.StartedAt.Format(time.RFC3339Nano)),
+		attribute.Int("consecutive_failures", res.ConsecutiveFailures),
+		attribute.Int("consecutive_successes", res.ConsecutiveSuccesses),
+		attribute.Int("attempts", res.Attempts),
+	)
+
+	if res.CircuitBreaker != "" {
+		cs.span.SetAttributes(attribute.String("circuit_breaker", string(res.CircuitBreaker)))
+	}
+
+	if res.LastSuccess != nil {
+		cs.span.SetAttributes(attribute.String("last_success", res.LastSuccess.Format(time.RFC3339Nano)))
+	}
+
+	if res.LastFailure != nil {
+		cs.span.SetAttributes(attribute.String("last_failure", res.LastFailure.Format(time.RFC3339Nano)))
+	}
+}
+
+// Measure runs all the registered health checks and returns summary status.
+// If the container runs in background mode, the latest results of the scheduled checks are returned instead.
 func (h *Health) Measure(ctx context.Context) Check {
-	h.mu.Lock()
-	defer h.mu.Unlock()
+	return h.measure(ctx, h.registered())
+}
 
+// MeasureTags is the same as Measure, but only the checks tagged with any of the tags are evaluated.
+func (h *Health) MeasureTags(ctx context.Context, tags ...string) Check {
+	return h.measure(ctx, h.tagged(tags))
+}
+
+// tagged returns the registered checks tagged with any of the tags sorted by name.
+func (h *Health) tagged(tags []string) []registeredCheck {
+	var checks []registeredCheck
+	for _, rc := range h.registered() {
+		if rc.config.hasAnyTag(tags) {
+			checks = append(checks, rc)
+		}
+	}
+
+	return checks
+}
+
+func (h *Health) measure(ctx context.Context, checks []registeredCheck) Check {
 	tracer := h.tp.Tracer(h.instrumentationName)
 
 	ctx, span := tracer.Start(ctx, "health.Measure")
 	defer span.End()
 
-	status := StatusOK
-	total := len(h.checks)
-	services := make(map[string]ServiceStatus)
-	resChan := make(chan checkResponse, total)
-	checkSpans := make(map[string]checkSpan)
+	span.SetAttributes(attribute.Int("checks", len(checks)))
+
+	var services map[string]ServiceStatus
+	if results, ok := h.scheduler.snapshot(); ok {
+		span.SetAttributes(attribute.Bool("background", true))
+
+		services = make(map[string]ServiceStatus, len(checks))
+		for _, rc := range checks {
+			// the checks that have not run yet since Start are failed, so that the service is not ready
+			// before every dependency is checked once
+			res, ok := results[rc.config.Name]
+			if !ok {
+				res = ServiceStatus{
+					IsOk:        false,
+					Message:     "health check has not run yet",
+					Skippable:   rc.config.SkipOnErr,
+					Criticality: rc.config.Criticality,
+				}
+			}
+			services[rc.config.Name] = res
+		}
+	} else {
+		services = h.coalesce(ctx, tracer, checks)
+	}
 
-	span.SetAttributes(attribute.Int("checks", total))
+	status := availability(services)
 
-	var wg sync.WaitGroup
-	wg.Add(total)
+	var stalled int
+	for _, n := range h.StalledChecks() {
+		stalled += n
+	}
 
-	go func() {
-		defer close(resChan)
+	span.SetAttributes(attribute.String("status", string(status)), attribute.Int("stalled_checks", stalled))
 
-		wg.Wait()
-	}()
+	c := newCheck(status, services, h.systemMetrics)
+	c.Build = h.build
+
+	return c
+}
+
+// runChecks executes the checks in parallel, every check within its own timeout
+// and all of them within the measure timeout of the container.
+func (h *Health) runChecks(ctx context.Context, tracer trace.Tracer, checks []registeredCheck) map[string]ServiceStatus {
+	if h.measureTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.measureTimeout)
+		defer cancel()
+	}
+
+	// every check waits for its dependencies measured along with it, the independent checks run in parallel
+	done := make(map[string]chan struct{}, len(checks))
+	for _, rc := range checks {
+		done[rc.config.Name] = make(chan struct{})
+	}
 
-	for _, c := range h.checks {
-		checkSpans[c.Name] = newCheckSpan(ctx, tracer, c.Name)
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		services = make(map[string]ServiceStatus, len(checks))
+	)
 
-		go func(c Config) {
+	wg.Add(len(checks))
+	for _, rc := range checks {
+		go func(rc registeredCheck) {
 			defer wg.Done()
+			defer close(done[rc.config.Name])
 
-			select {
-			case resChan <- checkResponse{c.Name, c.SkipOnErr, c.Check(ctx)}:
-			default:
+			res, cached := rc.state.cached(h.cacheTTL(rc.config), time.Now())
+			if !cached {
+				res = h.runDependentCheck(ctx, tracer, rc, h.measuredDependency(ctx, done, &mu, services))
 			}
-		}(c)
-
-	loop:
-		for {
-			select {
-			case <-time.After(c.Timeout):
-				services[c.Name] = ServiceStatus{
-					IsOk:      false,
-					Message:   "health check timed out",
-					Skippable: c.SkipOnErr,
-				}
-				status = getAvailability(status, c.SkipOnErr)
-
-				cs := checkSpans[c.Name]
-				cs.span.SetStatus(codes.Error, string(StatusTimeout))
-				cs.span.End()
-
-				break loop
-			case res := <-resChan:
-				cs := checkSpans[res.name]
-
-				if res.err != nil {
-					services[res.name] = ServiceStatus{
-						IsOk:      false,
-						Message:   res.err.Error(),
-						Skippable: c.SkipOnErr,
-					}
-					status = getAvailability(status, res.skipOnErr)
-
-					cs.span.RecordError(res.err)
-				} else {
-					services[res.name] = ServiceStatus{
-						IsOk:      true,
-						Message:   "",
-						Skippable: c.SkipOnErr,
-					}
-				}
 
-				cs.span.End()
+			mu.Lock()
+			services[rc.config.Name] = res
+			mu.Unlock()
+		}(rc)
+	}
+
+	wg.Wait()
+
+	return services
+}
+
+// measuredDependency returns the dependency lookup that waits for the dependencies measured along with the check.
+func (h *Health) measuredDependency(
+	ctx context.Context, done map[string]chan struct{}, mu *sync.Mutex, services map[string]ServiceStatus,
+) dependencyLookup {
+	return func(dep string) (ServiceStatus, bool) {
+		ch, ok := done[dep]
+		if !ok {
+			return h.dependencyResult(dep)
+		}
+
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ServiceStatus{}, false
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		return services[dep], true
+	}
+}
 
-				break loop
+// runCheck executes a single check within its timeout and records its outcome in the check state.
+// The context passed to the check is cancelled as soon as the timeout is reached,
+// the checks that do not return by then are counted as stalled.
+// A run abandoned because ctx is cancelled, e.g. by Stop, is not recorded.
+func (h *Health) runCheck(ctx context.Context, tracer trace.Tracer, c Config, st *checkState) ServiceStatus {
+	checkCtx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	cs := newCheckSpan(checkCtx, tracer, c.Name)
+	defer cs.span.End()
+
+	startedAt := time.Now()
+	if res, ok := st.allow(c, startedAt); !ok {
+		cs.span.SetStatus(codes.Error, "circuit breaker is open")
+		res.StartedAt = startedAt
+		return h.completeCheck(cs, c, st, res)
+	}
+
+	res := h.retryCheck(cs, c)
+	res.StartedAt = startedAt
+	res.Duration = time.Since(startedAt)
+
+	if errors.Is(ctx.Err(), context.Canceled) {
+		st.release(c)
+		res.Criticality = c.Criticality
+		return res
+	}
+
+	return h.completeCheck(cs, c, st, res)
+}
+
+// completeCheck records the check result in the check state and notifies the observers and the subscribers.
+func (h *Health) completeCheck(cs checkSpan, c Config, st *checkState, res ServiceStatus) ServiceStatus {
+	res.Criticality = c.Criticality
+	e := st.record(c, &res)
+	cs.setAttributes(res)
+
+	for _, o := range h.observers {
+		o.ObserveCheck(c.Name, res)
+	}
+
+	h.logResult(c.Name, res, e)
+
+	if e != nil {
+		h.events.publish(*e)
+	}
+
+	return res
+}
+
+func (h *Health) execCheck(cs checkSpan, c Config) ServiceStatus {
+	resChan := make(chan checkOutput, 1)
+	go func() {
+		resChan <- runCheckFunc(cs, c)
+	}()
+
+	select {
+	case <-cs.ctx.Done():
+		h.trackStalled(c.Name, resChan)
+
+		cs.span.SetStatus(codes.Error, string(StatusTimeout))
+
+		if !errors.Is(cs.ctx.Err(), context.DeadlineExceeded) {
+			return ServiceStatus{
+				IsOk:      false,
+				Message:   cs.ctx.Err().Error(),
+				Skippable: c.SkipOnErr,
+			}
+		}
+
+		return ServiceStatus{
+			IsOk:      false,
+			Message:   "health check timed out",
+			Skippable: c.SkipOnErr,
+			TimedOut:  true,
+		}
+	case out := <-resChan:
+		var pe *panicError
+		if errors.As(out.err, &pe) {
+			cs.span.RecordError(out.err)
+			cs.recordPanic(pe)
+
+			return ServiceStatus{
+				IsOk:      false,
+				Message:   out.err.Error(),
+				Skippable: c.SkipOnErr,
+				Panicked:  true,
+				Stack:     pe.stack,
 			}
 		}
+
+		res := ServiceStatus{
+			IsOk:      true,
+			Message:   "",
+			Skippable: c.SkipOnErr,
+		}
+
+		if out.err != nil {
+			cs.span.RecordError(out.err)
+
+			res.IsOk = false
+			res.Message = out.err.Error()
+		}
+
+		out.result.apply(&res)
+
+		return res
 	}
+}
+
+// trackStalled counts the check as stalled until it returns.
+func (h *Health) trackStalled(name string, resChan <-chan checkOutput) {
+	h.stalledMu.Lock()
+	h.stalled[name]++
+	h.stalledMu.Unlock()
+
+	go func() {
+		<-resChan
 
-	span.SetAttributes(attribute.String("status", string(status)))
+		h.stalledMu.Lock()
+		defer h.stalledMu.Unlock()
 
-	return newCheck(status, services)
+		if h.stalled[name]--; h.stalled[name] == 0 {
+			delete(h.stalled, name)
+		}
+	}()
 }
 
-func newCheck(statusText Status, services map[string]ServiceStatus) Check {
+// StalledChecks returns the number of check runs that are still running after their deadline, by check name.
+// A check that ignores the cancellation of its context stays here until it returns, which points to a stuck dependency.
+func (h *Health) StalledChecks() map[string]int {
+	h.stalledMu.Lock()
+	defer h.stalledMu.Unlock()
+
+	stalled := make(map[string]int, len(h.stalled))
+	for name, n := range h.stalled {
+		stalled[name] = n
+	}
+
+	return stalled
+}
+
+func newCheck(statusText Status, services map[string]ServiceStatus, systemMetrics SystemMetrics) Check {
 	return Check{
 		IsOK:      statusText == StatusOK || statusText == StatusPartiallyAvailable,
 		Status:    statusText,
 		Timestamp: time.Now(),
 		Services:  services,
-		System:    newSystemMetrics(),
+		System:    newSystemMetrics(systemMetrics),
 	}
 }
 
-func newSystemMetrics() System {
-	s := runtime.MemStats{}
-	runtime.ReadMemStats(&s)
+func availability(services map[string]ServiceStatus) Status {
+	status := StatusOK
+	for _, s := range services {
+		if s.Criticality == CriticalityInformational {
+			continue
+		}
 
-	return System{
-		Version:          runtime.Version(),
-		GoroutinesCount:  runtime.NumGoroutine(),
-		TotalAllocBytes:  int(s.TotalAlloc),
-		HeapObjectsCount: int(s.HeapObjects),
-		AllocBytes:       int(s.Alloc),
+		switch {
+		case !s.IsOk:
+			status = getAvailability(status, s.Skippable)
+		case s.Warning != "":
+			// the check passed in a degraded state
+			status = getAvailability(status, true)
+		}
 	}
+
+	return status
 }
 
 func getAvailability(s Status, skipOnErr bool) Status {
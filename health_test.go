@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -58,6 +59,104 @@ func TestDoubleRegister(t *testing.T) {
 	assert.Error(t, err, "registration with same name, but different details should still return an error, but did not")
 }
 
+func TestUnregister(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "foo",
+		Check: func(context.Context) error { return nil },
+	}, Config{
+		Name:  "bar",
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, StatusUnavailable, h.Measure(context.Background()).Status)
+
+	require.NoError(t, h.Unregister("bar"))
+	require.Error(t, h.Unregister("bar"), "unregistering a missing health check should return an error")
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status)
+	assert.Len(t, c.Services, 1)
+
+	// the name can be registered again
+	require.NoError(t, h.Register(Config{
+		Name:  "bar",
+		Check: func(context.Context) error { return nil },
+	}))
+}
+
+func TestReplace(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "foo",
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, StatusUnavailable, h.Measure(context.Background()).Status)
+
+	require.NoError(t, h.Replace(Config{
+		Name:      "foo",
+		SkipOnErr: true,
+		Check:     func(context.Context) error { return errors.New(checkErr) },
+	}))
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusPartiallyAvailable, c.Status)
+	assert.Equal(t, 1, c.Services["foo"].ConsecutiveFailures, "replace should reset the check state")
+
+	require.Error(t, h.Replace(Config{
+		Name:  "missing",
+		Check: func(context.Context) error { return nil },
+	}), "replacing a missing health check should return an error")
+	require.Error(t, h.Replace(Config{Name: ""}))
+}
+
+func TestChecks(t *testing.T) {
+	check := func(context.Context) error { return nil }
+
+	h, err := New(WithChecks(Config{Name: "foo", Check: check}, Config{Name: "bar", Timeout: time.Second, Check: check}))
+	require.NoError(t, err)
+
+	checks := h.Checks()
+	require.Len(t, checks, 2)
+	assert.Equal(t, "bar", checks[0].Name)
+	assert.Equal(t, time.Second, checks[0].Timeout)
+	assert.Equal(t, "foo", checks[1].Name)
+	assert.Equal(t, 2*time.Second, checks[1].Timeout, "default timeout should be set on registration")
+}
+
+func TestRegisterWhileMeasuring(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	h, err := New(WithChecks(Config{
+		Name: "slow",
+		Check: func(context.Context) error {
+			close(started)
+			<-release
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	done := make(chan Check)
+	go func() {
+		done <- h.Measure(context.Background())
+	}()
+
+	<-started
+
+	require.NoError(t, h.Register(Config{Name: "new", Check: func(context.Context) error { return nil }}))
+	require.NoError(t, h.Replace(Config{Name: "new", Check: func(context.Context) error { return nil }}))
+	require.NoError(t, h.Unregister("slow"))
+	assert.Len(t, h.Checks(), 1)
+
+	close(release)
+
+	c := <-done
+	assert.True(t, c.Services["slow"].IsOk, "running measurement should not be affected by unregistering")
+}
+
 func TestHealthHandler(t *testing.T) {
 	h, err := New()
 	require.NoError(t, err)
@@ -101,12 +200,299 @@ func TestHealthHandler(t *testing.T) {
 
 	assert.Equal(t, string(StatusPartiallyAvailable), body["status"], "body returned wrong status")
 
-	failure, ok := body["failures"]
-	assert.True(t, ok, "body returned nil failures field")
+	services, ok := body["service"]
+	assert.True(t, ok, "body returned nil service field")
+
+	svc, ok := services.(map[string]interface{})
+	assert.True(t, ok, "body returned wrong service field")
+
+	rabbit, ok := svc["rabbitmq"].(map[string]interface{})
+	require.True(t, ok, "body returned nil service.rabbitmq field")
+	assert.Equal(t, checkErr, rabbit["message"], "body returned wrong status for rabbitmq")
+
+	snail, ok := svc["snail-service"].(map[string]interface{})
+	require.True(t, ok, "body returned nil service.snail-service field")
+	assert.Equal(t, "health check timed out", snail["message"], "body returned wrong status for snail-service")
+}
+
+func TestMeasureTags(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "deadlock-detector",
+		Tags:  []string{TagLiveness},
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}, Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}, Config{
+		Name:  "cache",
+		Tags:  []string{TagLiveness, TagReadiness},
+		Check: func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	c := h.MeasureTags(context.Background(), TagReadiness)
+	assert.Equal(t, StatusOK, c.Status)
+	assert.Len(t, c.Services, 2)
+	assert.Contains(t, c.Services, "postgres", "untagged checks should be readiness checks")
+	assert.Contains(t, c.Services, "cache")
+
+	c = h.MeasureTags(context.Background(), TagLiveness)
+	assert.Equal(t, StatusUnavailable, c.Status)
+	assert.Len(t, c.Services, 2)
+	assert.Contains(t, c.Services, "deadlock-detector")
+	assert.Contains(t, c.Services, "cache")
+
+	c = h.MeasureTags(context.Background(), TagStartup)
+	assert.Equal(t, StatusOK, c.Status)
+	assert.Len(t, c.Services, 0)
+
+	assert.Len(t, h.Measure(context.Background()).Services, 3)
+
+	res := httptest.NewRecorder()
+	h.TagsHandler(TagLiveness).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+
+	res = httptest.NewRecorder()
+	h.ReadinessHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+func TestLivenessHandler(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}))
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	h.LivenessHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/liveness", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{"is_service_ok":true}`, res.Body.String(), "liveness without liveness checks should always be ok")
+
+	require.NoError(t, h.Register(Config{
+		Name:  "deadlock-detector",
+		Tags:  []string{TagLiveness},
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}))
+
+	res = httptest.NewRecorder()
+	h.LivenessHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/liveness", nil))
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+
+	var l Liveness
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&l))
+	assert.False(t, bool(l.IsOK))
+	assert.Equal(t, StatusUnavailable, l.Status)
+	assert.Len(t, l.Services, 1)
+	assert.Equal(t, checkErr, l.Services["deadlock-detector"].Message)
+}
+
+func TestMeasureRunsChecksInParallel(t *testing.T) {
+	h, err := New()
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		err = h.Register(Config{
+			Name:    fmt.Sprintf("slow-%d", i),
+			Timeout: 200 * time.Millisecond,
+			Check: func(context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	err = h.Register(Config{
+		Name:  "fast",
+		Check: func(context.Context) error { return nil },
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	c := h.Measure(context.Background())
+	elapsed := time.Since(start)
+
+	assert.Less(t, int64(elapsed), int64(500*time.Millisecond), "checks timeouts should not add up")
+	assert.Equal(t, StatusUnavailable, c.Status)
+	require.Len(t, c.Services, 11)
+	assert.True(t, c.Services["fast"].IsOk)
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "health check timed out", c.Services[fmt.Sprintf("slow-%d", i)].Message)
+	}
+}
+
+func TestMeasureTimeout(t *testing.T) {
+	h, err := New(WithMeasureTimeout(100*time.Millisecond), WithChecks(Config{
+		Name:    "slow",
+		Timeout: time.Second,
+		Check: func(context.Context) error {
+			time.Sleep(300 * time.Millisecond)
+			return nil
+		},
+	}, Config{
+		Name:  "fast",
+		Check: func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	start := time.Now()
+	c := h.Measure(context.Background())
+
+	assert.Less(t, int64(time.Since(start)), int64(250*time.Millisecond), "measure should respect its own timeout")
+	assert.Equal(t, StatusUnavailable, c.Status)
+	assert.True(t, c.Services["fast"].IsOk)
+	assert.Equal(t, "health check timed out", c.Services["slow"].Message)
+
+	_, err = New(WithMeasureTimeout(0))
+	require.Error(t, err)
+}
+
+func TestMeasureCancelsTimedOutChecks(t *testing.T) {
+	cancelled := make(chan struct{})
+	release := make(chan struct{})
+
+	h, err := New(WithChecks(Config{
+		Name:    "cancellable",
+		Timeout: 50 * time.Millisecond,
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			close(cancelled)
+			return ctx.Err()
+		},
+	}, Config{
+		Name:    "stuck",
+		Timeout: 50 * time.Millisecond,
+		Check: func(context.Context) error {
+			<-release
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, "health check timed out", c.Services["cancellable"].Message)
+	assert.Equal(t, "health check timed out", c.Services["stuck"].Message)
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("check context was not cancelled on timeout")
+	}
+
+	require.Eventually(t, func() bool {
+		return h.StalledChecks()["cancellable"] == 0
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, map[string]int{"stuck": 1}, h.StalledChecks())
+
+	close(release)
+
+	require.Eventually(t, func() bool {
+		return len(h.StalledChecks()) == 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestMeasureRecordsTimings(t *testing.T) {
+	var fail bool
+
+	h, err := New(WithChecks(Config{
+		Name: "flapping",
+		Check: func(context.Context) error {
+			time.Sleep(10 * time.Millisecond)
+			if fail {
+				return errors.New(checkErr)
+			}
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	before := time.Now()
+	res := h.Measure(context.Background()).Services["flapping"]
+	require.True(t, res.IsOk)
+	assert.GreaterOrEqual(t, int64(res.Duration), int64(10*time.Millisecond))
+	assert.False(t, res.StartedAt.Before(before))
+	require.NotNil(t, res.LastSuccess)
+	assert.Nil(t, res.LastFailure)
+	lastSuccess := *res.LastSuccess
+
+	fail = true
+	res = h.Measure(context.Background()).Services["flapping"]
+	require.False(t, res.IsOk)
+	require.NotNil(t, res.LastSuccess)
+	require.NotNil(t, res.LastFailure)
+	assert.Equal(t, lastSuccess, *res.LastSuccess, "last success should be kept between measurements")
+	assert.True(t, res.LastFailure.After(lastSuccess))
+
+	data, err := json.Marshal(res)
+	require.NoError(t, err)
+
+	body := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(data, &body))
+	for _, field := range []string{"duration", "started_at", "last_success", "last_failure"} {
+		assert.Contains(t, body, field)
+	}
+}
+
+func BenchmarkMeasure(b *testing.B) {
+	for _, n := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("checks-%d", n), func(b *testing.B) {
+			h, err := New()
+			require.NoError(b, err)
+
+			for i := 0; i < n; i++ {
+				err = h.Register(Config{
+					Name: fmt.Sprintf("check-%d", i),
+					Check: func(context.Context) error {
+						time.Sleep(time.Millisecond)
+						return nil
+					},
+				})
+				require.NoError(b, err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				h.Measure(context.Background())
+			}
+		})
+	}
+}
+
+func TestCriticality(t *testing.T) {
+	failing := func(context.Context) error { return errors.New(checkErr) }
+
+	h, err := New(WithChecks(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}, Config{
+		Name:        "feature-flags",
+		Criticality: CriticalityInformational,
+		Check:       failing,
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status, "informational failure should not affect the status")
+	assert.False(t, c.Services["feature-flags"].IsOk)
+	assert.Equal(t, checkErr, c.Services["feature-flags"].Message)
+	assert.Equal(t, CriticalityInformational, c.Services["feature-flags"].Criticality)
+	assert.Equal(t, CriticalityCritical, c.Services["postgres"].Criticality)
+
+	require.NoError(t, h.Register(Config{Name: "cache", Criticality: CriticalityDegraded, Check: failing}))
+	require.NoError(t, h.Register(Config{Name: "rabbitmq", SkipOnErr: true, Check: failing}))
+
+	c = h.Measure(context.Background())
+	assert.Equal(t, StatusPartiallyAvailable, c.Status)
+	assert.True(t, c.Services["cache"].Skippable)
+	assert.Equal(t, CriticalityDegraded, c.Services["rabbitmq"].Criticality)
+
+	require.NoError(t, h.Register(Config{Name: "mongodb", Criticality: CriticalityCritical, SkipOnErr: true, Check: failing}))
 
-	f, ok := failure.(map[string]interface{})
-	assert.True(t, ok, "body returned nil failures.rabbitmq field")
+	c = h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, c.Status, "criticality should take precedence over SkipOnErr")
 
-	assert.Equal(t, checkErr, f["rabbitmq"], "body returned wrong status for rabbitmq")
-	assert.Equal(t, string(StatusTimeout), f["snail-service"], "body returned wrong status for snail-service")
+	err = h.Register(Config{Name: "kafka", Criticality: "optional", Check: failing})
+	require.EqualError(t, err, `health check criticality "optional" is not supported`)
 }
@@ -0,0 +1,169 @@
+package health
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const contentTypeJSON = "application/json"
+
+// ContentTypeHealthJSON is the content type of the IETF health check response format,
+// see https://datatracker.ietf.org/doc/html/draft-inadarei-api-health-check.
+const ContentTypeHealthJSON = "application/health+json"
+
+// IETFStatus is the status of the IETF health check response format.
+type IETFStatus string
+
+// Possible IETF statuses
+const (
+	IETFStatusPass IETFStatus = "pass"
+	IETFStatusWarn IETFStatus = "warn"
+	IETFStatusFail IETFStatus = "fail"
+)
+
+type (
+	// ServiceInfo carries the service details reported in the IETF health check response format.
+	ServiceInfo struct {
+		// ServiceID is the unique identifier of the service.
+		ServiceID string
+		// Description is the human-friendly description of the service.
+		Description string
+		// Version is the public version of the service.
+		Version string
+		// ReleaseID is the version of the service implementation.
+		ReleaseID string
+	}
+
+	// IETFResponse represents the health check response in the IETF health check response format.
+	IETFResponse struct {
+		// Status is the overall status of the service.
+		Status IETFStatus `json:"status"`
+		// Version is the public version of the service.
+		Version string `json:"version,omitempty"`
+		// ReleaseID is the version of the service implementation.
+		ReleaseID string `json:"releaseId,omitempty"`
+		// ServiceID is the unique identifier of the service.
+		ServiceID string `json:"serviceId,omitempty"`
+		// Description is the human-friendly description of the service.
+		Description string `json:"description,omitempty"`
+		// Output is the raw error output, it is omitted for the pass status.
+		Output string `json:"output,omitempty"`
+		// Checks holds the checks details keyed by "{componentName}:{measurementName}".
+		Checks map[string][]IETFCheck `json:"checks,omitempty"`
+	}
+
+	// IETFCheck represents a single measurement of a check in the IETF health check response format.
+	IETFCheck struct {
+		// ComponentType is the type of the component.
+		ComponentType string `json:"componentType,omitempty"`
+		// ObservedValue is the value of the measurement.
+		ObservedValue interface{} `json:"observedValue,omitempty"`
+		// ObservedUnit is the unit of the observed value.
+		ObservedUnit string `json:"observedUnit,omitempty"`
+		// Status is the status of the check.
+		Status IETFStatus `json:"status"`
+		// Time is the time in which the measurement was made.
+		Time time.Time `json:"time"`
+		// Output is the raw error output, it is omitted for the pass status.
+		Output string `json:"output,omitempty"`
+	}
+)
+
+// NewIETFResponse converts the Measure result to the IETF health check response format.
+// The response time of every check is reported as the "{name}:responseTime" measurement,
+// and the observed value of a check with a structured result as the "{name}:observedValue" measurement.
+// The version and the release ID that are not set in the service info are taken from the build information.
+func NewIETFResponse(c Check, info ServiceInfo) IETFResponse {
+	res := IETFResponse{
+		Status:      ietfStatus(c.Status),
+		Version:     info.Version,
+		ReleaseID:   info.ReleaseID,
+		ServiceID:   info.ServiceID,
+		Description: info.Description,
+		Checks:      make(map[string][]IETFCheck, len(c.Services)),
+	}
+
+	if b := c.Build; b != nil {
+		if res.Version == "" {
+			res.Version = b.Version
+		}
+
+		if res.ReleaseID == "" {
+			res.ReleaseID = b.Release
+		}
+
+		if res.ReleaseID == "" {
+			res.ReleaseID = b.Revision
+		}
+	}
+
+	if res.Status != IETFStatusPass {
+		res.Output = string(c.Status)
+	}
+
+	for name, s := range c.Services {
+		check := IETFCheck{
+			ComponentType: "component",
+			ObservedValue: float64(s.Duration) / float64(time.Millisecond),
+			ObservedUnit:  "ms",
+			Status:        ietfCheckStatus(s),
+			Time:          s.StartedAt,
+		}
+
+		if check.Status != IETFStatusPass {
+			check.Output = s.Message
+			if check.Output == "" {
+				check.Output = s.Warning
+			}
+		}
+
+		res.Checks[name+":responseTime"] = []IETFCheck{check}
+
+		if s.ObservedValue != nil {
+			observed := check
+			observed.ObservedValue = s.ObservedValue
+			observed.ObservedUnit = s.ObservedUnit
+
+			res.Checks[name+":observedValue"] = []IETFCheck{observed}
+		}
+	}
+
+	return res
+}
+
+func ietfStatus(s Status) IETFStatus {
+	switch s {
+	case StatusOK:
+		return IETFStatusPass
+	case StatusPartiallyAvailable:
+		return IETFStatusWarn
+	default:
+		return IETFStatusFail
+	}
+}
+
+func ietfCheckStatus(s ServiceStatus) IETFStatus {
+	switch {
+	case s.IsOk && s.Message == "" && s.Warning == "":
+		return IETFStatusPass
+	case s.IsOk, s.Skippable:
+		// the check failed below its failure threshold, passed with a warning or the failure is skipped
+		return IETFStatusWarn
+	default:
+		return IETFStatusFail
+	}
+}
+
+// acceptsIETF tells if the client accepts the IETF health check response format.
+func acceptsIETF(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && mediaType == ContentTypeHealthJSON {
+			return true
+		}
+	}
+
+	return false
+}
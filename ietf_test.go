@@ -0,0 +1,82 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIETFResponse(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}, Config{
+		Name:      "rabbitmq",
+		SkipOnErr: true,
+		Check:     func(context.Context) error { return errors.New(checkErr) },
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	res := NewIETFResponse(c, ServiceInfo{ServiceID: "orders", Version: "1", ReleaseID: "1.2.3"})
+
+	assert.Equal(t, IETFStatusWarn, res.Status)
+	assert.Equal(t, string(StatusPartiallyAvailable), res.Output)
+	assert.Equal(t, "orders", res.ServiceID)
+	assert.Equal(t, "1", res.Version)
+	assert.Equal(t, "1.2.3", res.ReleaseID)
+	require.Len(t, res.Checks, 2)
+
+	pg := res.Checks["postgres:responseTime"]
+	require.Len(t, pg, 1)
+	assert.Equal(t, IETFStatusPass, pg[0].Status)
+	assert.Equal(t, "ms", pg[0].ObservedUnit)
+	assert.Empty(t, pg[0].Output)
+	assert.Equal(t, c.Services["postgres"].StartedAt, pg[0].Time)
+
+	mq := res.Checks["rabbitmq:responseTime"]
+	require.Len(t, mq, 1)
+	assert.Equal(t, IETFStatusWarn, mq[0].Status)
+	assert.Equal(t, checkErr, mq[0].Output)
+}
+
+func TestReadinessHandlerIETF(t *testing.T) {
+	checks := WithChecks(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	})
+
+	h, err := New(checks, WithServiceInfo(ServiceInfo{ReleaseID: "1.2.3"}))
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	h.ReadinessHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/readiness", nil))
+	assert.Equal(t, contentTypeJSON, res.Header().Get("Content-Type"))
+
+	req := httptest.NewRequest(http.MethodGet, "/readiness", nil)
+	req.Header.Set("Accept", "application/json;q=0.9, application/health+json")
+
+	res = httptest.NewRecorder()
+	h.ReadinessHandler().ServeHTTP(res, req)
+	assert.Equal(t, ContentTypeHealthJSON, res.Header().Get("Content-Type"))
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+
+	body := make(map[string]interface{})
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	assert.Equal(t, "fail", body["status"])
+	assert.Equal(t, "1.2.3", body["releaseId"])
+	assert.Contains(t, body["checks"], "postgres:responseTime")
+
+	h, err = New(checks, WithIETFFormat())
+	require.NoError(t, err)
+
+	res = httptest.NewRecorder()
+	h.ReadinessHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/readiness", nil))
+	assert.Equal(t, ContentTypeHealthJSON, res.Header().Get("Content-Type"))
+}
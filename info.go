@@ -0,0 +1,112 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// buildInfoKey is the key of the build information in the info response, see WithBuildInfo.
+const buildInfoKey = "build"
+
+type (
+	// InfoFunc is the func which returns the details of an info contributor.
+	InfoFunc func(context.Context) (interface{}, error)
+
+	// InfoConfig carries the parameters of an info contributor.
+	InfoConfig struct {
+		// Name is the key of the contributor details in the info response.
+		Name string
+		// Info is the func which returns the contributor details, they must be JSON serializable.
+		Info InfoFunc
+	}
+
+	// InfoError is reported in the info response in place of the details of a failed contributor.
+	InfoError struct {
+		// Error is the error message of the contributor.
+		Error string `json:"error"`
+	}
+)
+
+// StaticInfo returns an InfoFunc that always returns the details, e.g. the region or the enabled features.
+func StaticInfo(details interface{}) InfoFunc {
+	return func(context.Context) (interface{}, error) {
+		return details, nil
+	}
+}
+
+// RegisterInfo registers an info contributor, see Info.
+func (h *Health) RegisterInfo(c InfoConfig) error {
+	if c.Name == "" {
+		return errors.New("info contributor must have a name to be registered")
+	}
+
+	if c.Info == nil {
+		return fmt.Errorf("info contributor %q must have an info func to be registered", c.Name)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.infos[c.Name]; ok {
+		return fmt.Errorf("info contributor %q is already registered", c.Name)
+	}
+
+	h.infos[c.Name] = c
+
+	return nil
+}
+
+// UnregisterInfo removes a registered info contributor.
+func (h *Health) UnregisterInfo(name string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.infos[name]; !ok {
+		return fmt.Errorf("info contributor %q is not registered", name)
+	}
+
+	delete(h.infos, name)
+
+	return nil
+}
+
+// Info returns the details of all the registered info contributors keyed by their names, along with the build
+// information keyed by "build" if it is set with WithBuildInfo and there is no contributor with the same name.
+// The details of a failed contributor are replaced by an InfoError.
+func (h *Health) Info(ctx context.Context) map[string]interface{} {
+	h.mu.Lock()
+	infos := make([]InfoConfig, 0, len(h.infos))
+	for _, c := range h.infos {
+		infos = append(infos, c)
+	}
+	h.mu.Unlock()
+
+	res := make(map[string]interface{}, len(infos)+1)
+	if h.build != nil {
+		res[buildInfoKey] = h.build
+	}
+
+	for _, c := range infos {
+		details, err := c.Info(ctx)
+		if err != nil {
+			res[c.Name] = InfoError{Error: err.Error()}
+			continue
+		}
+
+		res[c.Name] = details
+	}
+
+	return res
+}
+
+// InfoHandler returns an info HTTP handler (http.HandlerFunc).
+func (h *Health) InfoHandler() http.Handler {
+	return http.HandlerFunc(h.InfoHandlerFunc)
+}
+
+// InfoHandlerFunc is the info HTTP handler function, it responds with the merged details of the info contributors.
+func (h *Health) InfoHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, contentTypeJSON, h.Info(r.Context()), StatusOK, handlerConfig{})
+}
@@ -0,0 +1,76 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfoContributors(t *testing.T) {
+	h, err := New(WithBuildInfo(BuildInfo{Version: "1.2.3"}), WithInfoContributors(InfoConfig{
+		Name: "region",
+		Info: StaticInfo("eu-west-1"),
+	}, InfoConfig{
+		Name: "features",
+		Info: StaticInfo(map[string]bool{"new-checkout": true}),
+	}))
+	require.NoError(t, err)
+
+	err = h.RegisterInfo(InfoConfig{Name: "region", Info: StaticInfo("us-east-1")})
+	require.EqualError(t, err, `info contributor "region" is already registered`)
+
+	require.Error(t, h.RegisterInfo(InfoConfig{Info: StaticInfo("")}))
+	require.Error(t, h.RegisterInfo(InfoConfig{Name: "nil"}))
+
+	require.NoError(t, h.RegisterInfo(InfoConfig{
+		Name: "brokers",
+		Info: func(context.Context) (interface{}, error) { return nil, errors.New("kafka metadata is not available") },
+	}))
+
+	res := httptest.NewRecorder()
+	h.InfoHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/info", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, contentTypeJSON, res.Header().Get("Content-Type"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	assert.Equal(t, "eu-west-1", body["region"])
+	assert.Equal(t, map[string]interface{}{"new-checkout": true}, body["features"])
+	assert.Equal(t, map[string]interface{}{"error": "kafka metadata is not available"}, body["brokers"])
+	assert.Equal(t, "1.2.3", body["build"].(map[string]interface{})["version"])
+
+	require.NoError(t, h.UnregisterInfo("brokers"))
+	require.Error(t, h.UnregisterInfo("brokers"))
+	assert.NotContains(t, h.Info(context.Background()), "brokers")
+}
+
+func TestInfoConcurrentRegistration(t *testing.T) {
+	h, err := New()
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, h.RegisterInfo(InfoConfig{Name: fmt.Sprintf("contributor-%d", i), Info: StaticInfo(i)}))
+		}(i)
+
+		go func() {
+			defer wg.Done()
+			h.Info(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, h.Info(context.Background()), 10)
+}
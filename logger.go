@@ -0,0 +1,144 @@
+package health
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// Field is a structured logging field.
+	Field struct {
+		Key   string
+		Value interface{}
+	}
+
+	// Logger is the structured logger used to log the checks outcomes, see WithLogger.
+	Logger interface {
+		Info(msg string, fields ...Field)
+		Warn(msg string, fields ...Field)
+		Error(msg string, fields ...Field)
+	}
+
+	nopLogger struct{}
+
+	zapLogger struct {
+		l *zap.Logger
+	}
+
+	stdLogger struct {
+		l *log.Logger
+	}
+)
+
+// F is a shorthand for creating a Field.
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// NewNopLogger returns the logger that discards all the messages.
+func NewNopLogger() Logger {
+	return nopLogger{}
+}
+
+func (nopLogger) Info(string, ...Field)  {}
+func (nopLogger) Warn(string, ...Field)  {}
+func (nopLogger) Error(string, ...Field) {}
+
+// NewZapLogger returns the logger that writes to the zap logger.
+func NewZapLogger(l *zap.Logger) Logger {
+	return zapLogger{l: l}
+}
+
+func (z zapLogger) Info(msg string, fields ...Field) {
+	z.l.Info(msg, z.fields(fields)...)
+}
+
+func (z zapLogger) Warn(msg string, fields ...Field) {
+	z.l.Warn(msg, z.fields(fields)...)
+}
+
+func (z zapLogger) Error(msg string, fields ...Field) {
+	z.l.Error(msg, z.fields(fields)...)
+}
+
+func (z zapLogger) fields(fields []Field) []zap.Field {
+	zf := make([]zap.Field, 0, len(fields))
+	for _, f := range fields {
+		zf = append(zf, zap.Any(f.Key, f.Value))
+	}
+
+	return zf
+}
+
+// NewStdLogger returns the logger that writes to the standard library logger,
+// the fields are appended to the message as key=value pairs.
+func NewStdLogger(l *log.Logger) Logger {
+	return stdLogger{l: l}
+}
+
+func (s stdLogger) Info(msg string, fields ...Field) {
+	s.print("INFO", msg, fields)
+}
+
+func (s stdLogger) Warn(msg string, fields ...Field) {
+	s.print("WARN", msg, fields)
+}
+
+func (s stdLogger) Error(msg string, fields ...Field) {
+	s.print("ERROR", msg, fields)
+}
+
+func (s stdLogger) print(level string, msg string, fields []Field) {
+	var b strings.Builder
+	b.WriteString(level)
+	b.WriteString(" ")
+	b.WriteString(msg)
+
+	for _, f := range fields {
+		if v, ok := f.Value.(string); ok {
+			fmt.Fprintf(&b, " %s=%q", f.Key, v)
+		} else {
+			fmt.Fprintf(&b, " %s=%v", f.Key, f.Value)
+		}
+	}
+
+	s.l.Print(b.String())
+}
+
+// logResult logs the failures, the timeouts and the recoveries of a check.
+func (h *Health) logResult(name string, res ServiceStatus, e *Event) {
+	fields := []Field{
+		F("check", name),
+		F("duration", res.Duration),
+		F("consecutive_failures", res.ConsecutiveFailures),
+	}
+
+	switch {
+	case res.Failed() && res.IsOk:
+		// the failure is below the failure threshold
+		h.logger.Warn(failureMessage(res), append(fields, F("error", res.Message))...)
+	case res.Failed() && res.Panicked:
+		h.logger.Error(failureMessage(res), append(fields, F("error", res.Message), F("stack", res.Stack))...)
+	case res.Failed():
+		h.logger.Error(failureMessage(res), append(fields, F("error", res.Message), F("skippable", res.Skippable))...)
+	case res.IsOk && res.Warning != "":
+		h.logger.Warn("health check degraded", append(fields, F("warning", res.Warning))...)
+	case e != nil && e.Current == StatusOK:
+		h.logger.Info("health check recovered", append(fields, F("previous_status", string(e.Previous)))...)
+	}
+}
+
+func failureMessage(res ServiceStatus) string {
+	if res.TimedOut {
+		return "health check timed out"
+	}
+
+	if res.Panicked {
+		return "health check panicked"
+	}
+
+	return "health check failed"
+}
@@ -0,0 +1,75 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewStdLogger(log.New(&buf, "", 0))
+
+	l.Warn("health check failed", F("check", "postgres"), F("consecutive_failures", 2))
+	assert.Equal(t, "WARN health check failed check=\"postgres\" consecutive_failures=2\n", buf.String())
+}
+
+func TestWithLogger(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	var fail bool
+	h, err := New(WithLogger(NewZapLogger(zap.New(core))), WithChecks(Config{
+		Name:             "postgres",
+		FailureThreshold: 2,
+		Check: func(context.Context) error {
+			if fail {
+				return errors.New(checkErr)
+			}
+			return nil
+		},
+	}, Config{
+		Name:    "snail",
+		Timeout: 10 * time.Millisecond,
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}))
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+
+	timeouts := logs.FilterMessage("health check timed out").FilterField(zap.String("check", "snail")).All()
+	require.Len(t, timeouts, 1)
+	assert.Equal(t, zapcore.ErrorLevel, timeouts[0].Level)
+	assert.Zero(t, logs.FilterField(zap.String("check", "postgres")).Len(), "successful checks should not be logged")
+
+	fail = true
+	h.Measure(context.Background())
+	h.Measure(context.Background())
+
+	failures := logs.FilterMessage("health check failed").All()
+	require.Len(t, failures, 2)
+	assert.Equal(t, zapcore.WarnLevel, failures[0].Level, "failure below the threshold should be a warning")
+	assert.Equal(t, zapcore.ErrorLevel, failures[1].Level)
+	assert.Equal(t, checkErr, failures[1].ContextMap()["error"])
+
+	fail = false
+	h.Measure(context.Background())
+
+	recoveries := logs.FilterMessage("health check recovered").All()
+	require.Len(t, recoveries, 1)
+	assert.Equal(t, "postgres", recoveries[0].ContextMap()["check"])
+
+	_, err = New(WithLogger(nil))
+	require.Error(t, err)
+}
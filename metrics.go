@@ -0,0 +1,272 @@
+package health
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"go.opentelemetry.io/otel/metric/instrument/syncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/syncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+)
+
+// checkKey is the attribute carrying the check name on the spans and the metrics.
+const checkKey = attribute.Key("check")
+
+// Possible check outcomes recorded in the outcomes counter.
+const (
+	outcomeOK      = "ok"
+	outcomeFailed  = "failed"
+	outcomeTimeout = "timeout"
+	outcomeSkipped = "skipped"
+	outcomeWarn    = "warn"
+)
+
+// meterObserver records the checks results as OpenTelemetry metrics.
+type meterObserver struct {
+	duration syncfloat64.Histogram
+	outcomes syncint64.Counter
+	panics   syncint64.Counter
+	status   asyncint64.Gauge
+	breakers asyncint64.Gauge
+	build    asyncint64.Gauge
+	observed asyncfloat64.Gauge
+
+	mu         sync.Mutex
+	lastStatus Status
+	lastBuild  *BuildInfo
+	// lastBreakers are the latest circuit breaker states of the checks that have the breaker configured.
+	lastBreakers map[string]BreakerState
+	// lastObserved are the latest numeric observed values of the checks, see Config.CheckWithResult.
+	lastObserved map[string]observedValue
+}
+
+type observedValue struct {
+	value float64
+	unit  string
+}
+
+func newMeterObserver(mp metric.MeterProvider, instrumentationName string) (*meterObserver, error) {
+	meter := mp.Meter(instrumentationName)
+
+	duration, err := meter.SyncFloat64().Histogram(
+		"health.check.duration",
+		instrument.WithUnit(unit.Milliseconds),
+		instrument.WithDescription("Duration of the health checks runs"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes, err := meter.SyncInt64().Counter(
+		"health.check.outcomes",
+		instrument.WithDescription("Number of the health checks runs by outcome: ok, warn, failed, timeout or skipped"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	panics, err := meter.SyncInt64().Counter(
+		"health.check.panics",
+		instrument.WithDescription("Number of the health checks runs that panicked"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := meter.AsyncInt64().Gauge(
+		"health.status",
+		instrument.WithDescription("Overall health status, 1 for the current status and 0 for the others"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	breakers, err := meter.AsyncInt64().Gauge(
+		"health.check.circuit_breaker",
+		instrument.WithDescription("Circuit breaker state of the health checks, 1 for the current state and 0 for the others"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	build, err := meter.AsyncInt64().Gauge(
+		"health.build.info",
+		instrument.WithDescription("Build information of the service, always 1, the details are in the attributes"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	observed, err := meter.AsyncFloat64().Gauge(
+		"health.check.observed_value",
+		instrument.WithDescription("Latest value observed by the health checks with a structured result"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &meterObserver{
+		duration:     duration,
+		outcomes:     outcomes,
+		panics:       panics,
+		status:       status,
+		breakers:     breakers,
+		build:        build,
+		observed:     observed,
+		lastBreakers: make(map[string]BreakerState),
+		lastObserved: make(map[string]observedValue),
+	}
+
+	if err := meter.RegisterCallback([]instrument.Asynchronous{status}, o.observeStatusGauge); err != nil {
+		return nil, err
+	}
+
+	if err := meter.RegisterCallback([]instrument.Asynchronous{breakers}, o.observeBreakersGauge); err != nil {
+		return nil, err
+	}
+
+	if err := meter.RegisterCallback([]instrument.Asynchronous{build}, o.observeBuildGauge); err != nil {
+		return nil, err
+	}
+
+	if err := meter.RegisterCallback([]instrument.Asynchronous{observed}, o.observeObservedGauge); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// ObserveCheck implements Observer.
+func (o *meterObserver) ObserveCheck(name string, s ServiceStatus) {
+	ctx := context.Background()
+	check := checkKey.String(name)
+
+	o.duration.Record(ctx, float64(s.Duration.Microseconds())/1000, check)
+	o.outcomes.Add(ctx, 1, check,
+		attribute.String("outcome", checkOutcome(s)),
+		attribute.String("criticality", string(s.Criticality)),
+	)
+
+	if s.Panicked {
+		o.panics.Add(ctx, 1, check)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if s.CircuitBreaker != "" {
+		o.lastBreakers[name] = s.CircuitBreaker
+	}
+
+	if v, ok := s.ObservedFloat(); ok {
+		o.lastObserved[name] = observedValue{value: v, unit: s.ObservedUnit}
+	}
+}
+
+// ForgetCheck implements CheckForgetter.
+func (o *meterObserver) ForgetCheck(name string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.lastBreakers, name)
+	delete(o.lastObserved, name)
+}
+
+// ObserveStatus implements Observer.
+func (o *meterObserver) ObserveStatus(s Status) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.lastStatus = s
+}
+
+func (o *meterObserver) observeStatusGauge(ctx context.Context) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.lastStatus == "" {
+		return
+	}
+
+	for _, s := range []Status{StatusOK, StatusPartiallyAvailable, StatusUnavailable, StatusTimeout} {
+		var value int64
+		if s == o.lastStatus {
+			value = 1
+		}
+
+		o.status.Observe(ctx, value, attribute.String("status", string(s)))
+	}
+}
+
+func (o *meterObserver) observeBreakersGauge(ctx context.Context) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for name, current := range o.lastBreakers {
+		for _, s := range []BreakerState{BreakerClosed, BreakerOpen, BreakerHalfOpen} {
+			var value int64
+			if s == current {
+				value = 1
+			}
+
+			o.breakers.Observe(ctx, value, checkKey.String(name), attribute.String("state", string(s)))
+		}
+	}
+}
+
+func (o *meterObserver) observeObservedGauge(ctx context.Context) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for name, v := range o.lastObserved {
+		o.observed.Observe(ctx, v.value, checkKey.String(name), attribute.String("unit", v.unit))
+	}
+}
+
+// ObserveBuild implements BuildObserver.
+func (o *meterObserver) ObserveBuild(b BuildInfo) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.lastBuild = &b
+}
+
+func (o *meterObserver) observeBuildGauge(ctx context.Context) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.lastBuild == nil {
+		return
+	}
+
+	o.build.Observe(ctx, 1,
+		attribute.String("version", o.lastBuild.Version),
+		attribute.String("release", o.lastBuild.Release),
+		attribute.String("module_version", o.lastBuild.ModuleVersion),
+		attribute.String("revision", o.lastBuild.Revision),
+		attribute.Bool("dirty", o.lastBuild.Dirty),
+		attribute.String("go_version", o.lastBuild.GoVersion),
+	)
+}
+
+// checkOutcome returns the outcome of the check run, regardless of the check thresholds and criticality.
+// The checks that were not run because of a dependency or an open circuit breaker are skipped.
+func checkOutcome(s ServiceStatus) string {
+	switch {
+	case s.Skipped, s.ShortCircuited:
+		return outcomeSkipped
+	case s.TimedOut:
+		return outcomeTimeout
+	case s.Failed():
+		return outcomeFailed
+	case s.Warning != "":
+		return outcomeWarn
+	default:
+		return outcomeOK
+	}
+}
@@ -0,0 +1,154 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metrictest"
+)
+
+func TestWithMeterProvider(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+
+	h, err := New(WithMeterProvider(mp, "test.test"), WithBuildInfo(BuildInfo{Version: "1.2.3"}), WithChecks(Config{
+		Name:           "postgres",
+		CircuitBreaker: &CircuitBreaker{FailureThreshold: 1, CoolDown: time.Second},
+		Check:          func(context.Context) error { return nil },
+	}, Config{
+		Name:      "rabbitmq",
+		SkipOnErr: true,
+		Check:     func(context.Context) error { return errors.New(checkErr) },
+	}, Config{
+		Name:    "snail",
+		Timeout: 10 * time.Millisecond,
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}, Config{
+		Name:  "mongo",
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}, Config{
+		Name:  "kafka",
+		Check: func(context.Context) error { panic("nil client") },
+	}, Config{
+		Name:      "orders",
+		DependsOn: []string{"mongo"},
+		Check:     func(context.Context) error { return nil },
+	}, Config{
+		Name: "replica",
+		CheckWithResult: func(context.Context) (Result, error) {
+			return Result{ObservedValue: 1500, ObservedUnit: "ms", Warning: "replication lag is above 1s"}, nil
+		},
+	}))
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+	h.Measure(context.Background())
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	for name, outcome := range map[string]string{
+		"postgres": outcomeOK,
+		"rabbitmq": outcomeFailed,
+		"snail":    outcomeTimeout,
+		"mongo":    outcomeFailed,
+		"kafka":    outcomeFailed,
+		"orders":   outcomeSkipped,
+		"replica":  outcomeWarn,
+	} {
+		criticality := CriticalityCritical
+		if name == "rabbitmq" {
+			criticality = CriticalityDegraded
+		}
+
+		rec, err := exp.GetByNameAndAttributes("health.check.outcomes", []attribute.KeyValue{
+			checkKey.String(name),
+			attribute.String("outcome", outcome),
+			attribute.String("criticality", string(criticality)),
+		})
+		require.NoError(t, err, name)
+		assert.Equal(t, int64(2), rec.Sum.AsInt64(), name)
+
+		rec, err = exp.GetByNameAndAttributes("health.check.duration", []attribute.KeyValue{checkKey.String(name)})
+		require.NoError(t, err, name)
+		assert.Equal(t, uint64(2), rec.Count, name)
+	}
+
+	rec, err := exp.GetByNameAndAttributes("health.check.panics", []attribute.KeyValue{checkKey.String("kafka")})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), rec.Sum.AsInt64())
+
+	rec, err = exp.GetByNameAndAttributes("health.check.circuit_breaker", []attribute.KeyValue{
+		checkKey.String("postgres"),
+		attribute.String("state", string(BreakerClosed)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), rec.LastValue.AsInt64())
+
+	rec, err = exp.GetByNameAndAttributes("health.check.observed_value", []attribute.KeyValue{
+		checkKey.String("replica"),
+		attribute.String("unit", "ms"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1500.0, rec.LastValue.AsFloat64())
+
+	build := h.build
+	rec, err = exp.GetByNameAndAttributes("health.build.info", []attribute.KeyValue{
+		attribute.String("version", "1.2.3"),
+		attribute.String("release", ""),
+		attribute.String("module_version", build.ModuleVersion),
+		attribute.String("revision", build.Revision),
+		attribute.Bool("dirty", build.Dirty),
+		attribute.String("go_version", build.GoVersion),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), rec.LastValue.AsInt64())
+
+	rec, err = exp.GetByNameAndAttributes("health.status", []attribute.KeyValue{
+		attribute.String("status", string(StatusUnavailable)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), rec.LastValue.AsInt64())
+
+	rec, err = exp.GetByNameAndAttributes("health.status", []attribute.KeyValue{
+		attribute.String("status", string(StatusOK)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), rec.LastValue.AsInt64())
+}
+
+func TestMeterObserverForgetCheck(t *testing.T) {
+	mp, exp := metrictest.NewTestMeterProvider()
+
+	h, err := New(WithMeterProvider(mp, "test.test"), WithChecks(Config{
+		Name:           "replica",
+		CircuitBreaker: &CircuitBreaker{FailureThreshold: 1, CoolDown: time.Second},
+		CheckWithResult: func(context.Context) (Result, error) {
+			return Result{ObservedValue: 1500, ObservedUnit: "ms"}, nil
+		},
+	}))
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+	require.NoError(t, h.Unregister("replica"))
+
+	require.NoError(t, exp.Collect(context.Background()))
+
+	_, err = exp.GetByNameAndAttributes("health.check.observed_value", []attribute.KeyValue{
+		checkKey.String("replica"),
+		attribute.String("unit", "ms"),
+	})
+	require.Error(t, err, "observed value of the unregistered check should not be exported")
+
+	_, err = exp.GetByNameAndAttributes("health.check.circuit_breaker", []attribute.KeyValue{
+		checkKey.String("replica"),
+		attribute.String("state", string(BreakerClosed)),
+	})
+	require.Error(t, err, "circuit breaker of the unregistered check should not be exported")
+}
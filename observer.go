@@ -0,0 +1,68 @@
+package health
+
+import "time"
+
+// Observer is notified of the checks results, it is used to export them, e.g. as metrics.
+// The methods are called concurrently from the goroutines running the checks and must not block.
+type Observer interface {
+	// ObserveCheck is called with the result of every check run.
+	ObserveCheck(name string, s ServiceStatus)
+	// ObserveStatus is called with the overall status of all the registered checks every time it is re-evaluated.
+	ObserveStatus(s Status)
+}
+
+// CheckForgetter is an Observer notified of the checks that are unregistered or replaced,
+// so that it stops exporting their latest results.
+type CheckForgetter interface {
+	// ForgetCheck is called with the name of the check when it is unregistered or replaced.
+	ForgetCheck(name string)
+}
+
+// forgetCheck notifies the observers implementing CheckForgetter of the removed check.
+func (h *Health) forgetCheck(name string) {
+	for _, o := range h.observers {
+		if f, ok := o.(CheckForgetter); ok {
+			f.ForgetCheck(name)
+		}
+	}
+}
+
+// observeStatus notifies the observers and the subscribers of the overall status
+// computed from the latest results of all the checks.
+func (h *Health) observeStatus() {
+	h.events.statusMu.Lock()
+	defer h.events.statusMu.Unlock()
+
+	status := h.overallStatus()
+	for _, o := range h.observers {
+		o.ObserveStatus(status)
+	}
+
+	if status == h.events.status {
+		return
+	}
+
+	now := time.Now()
+	h.events.publish(Event{
+		Previous:  h.events.status,
+		Current:   status,
+		Timestamp: now,
+		Since:     h.events.changedAt,
+	})
+
+	h.events.status = status
+	h.events.changedAt = now
+}
+
+// overallStatus returns the status of all the registered checks according to their latest results,
+// the checks that have not run yet are not taken into account.
+func (h *Health) overallStatus() Status {
+	services := make(map[string]ServiceStatus)
+	for _, rc := range h.registered() {
+		if res, ok := rc.state.lastResult(); ok {
+			services[rc.config.Name] = res
+		}
+	}
+
+	return availability(services)
+}
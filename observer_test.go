@@ -0,0 +1,63 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingObserver struct {
+	mu       sync.Mutex
+	checks   map[string][]ServiceStatus
+	statuses []Status
+}
+
+func (o *recordingObserver) ObserveCheck(name string, s ServiceStatus) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.checks == nil {
+		o.checks = make(map[string][]ServiceStatus)
+	}
+	o.checks[name] = append(o.checks[name], s)
+}
+
+func (o *recordingObserver) ObserveStatus(s Status) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.statuses = append(o.statuses, s)
+}
+
+func TestObserver(t *testing.T) {
+	o := new(recordingObserver)
+
+	h, err := New(WithObserver(o), WithChecks(Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}, Config{
+		Name:  "liveness",
+		Tags:  []string{TagLiveness},
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}))
+	require.NoError(t, err)
+
+	h.MeasureTags(context.Background(), TagReadiness)
+
+	assert.Len(t, o.checks["postgres"], 1)
+	assert.Len(t, o.checks["liveness"], 0)
+	assert.Equal(t, []Status{StatusOK}, o.statuses)
+
+	h.MeasureTags(context.Background(), TagLiveness)
+
+	assert.Len(t, o.checks["liveness"], 1)
+	assert.Equal(t, checkErr, o.checks["liveness"][0].Message)
+	assert.Equal(t, []Status{StatusOK, StatusUnavailable}, o.statuses, "overall status should include all the checks")
+
+	_, err = New(WithObserver(nil))
+	require.Error(t, err)
+}
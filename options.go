@@ -1,8 +1,11 @@
 package health
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -22,6 +25,20 @@ func WithChecks(checks ...Config) Option {
 	}
 }
 
+// WithStartupTasks adds named startup tasks that must be completed with Health.CompleteStartupTask
+// before the service is reported as started.
+func WithStartupTasks(names ...string) Option {
+	return func(h *Health) error {
+		for _, name := range names {
+			if err := h.AddStartupTask(name); err != nil {
+				return fmt.Errorf("could not add startup task %q: %w", name, err)
+			}
+		}
+
+		return nil
+	}
+}
+
 // WithTracerProvider sets trace provider for the checks and instrumentation name that will be used
 // for tracer from trace provider.
 func WithTracerProvider(tp trace.TracerProvider, instrumentationName string) Option {
@@ -32,3 +49,178 @@ func WithTracerProvider(tp trace.TracerProvider, instrumentationName string) Opt
 		return nil
 	}
 }
+
+// WithMeterProvider sets meter provider for the checks metrics and instrumentation name that will be used
+// for meter from meter provider. The checks durations, the checks outcomes and the overall status are recorded.
+func WithMeterProvider(mp metric.MeterProvider, instrumentationName string) Option {
+	return func(h *Health) error {
+		o, err := newMeterObserver(mp, instrumentationName)
+		if err != nil {
+			return fmt.Errorf("could not create health checks metrics: %w", err)
+		}
+
+		h.observers = append(h.observers, o)
+
+		return nil
+	}
+}
+
+// WithLogger sets the logger for the checks failures, timeouts and recoveries.
+func WithLogger(l Logger) Option {
+	return func(h *Health) error {
+		if l == nil {
+			return errors.New("health checks logger must not be nil")
+		}
+
+		h.logger = l
+
+		return nil
+	}
+}
+
+// WithMeasureTimeout sets the overall time budget of Measure. The checks run in parallel, every check within
+// its own Config.Timeout, and the checks still running when the budget is exhausted are reported as timed out.
+func WithMeasureTimeout(timeout time.Duration) Option {
+	return func(h *Health) error {
+		if timeout <= 0 {
+			return errors.New("health checks measure timeout must be positive")
+		}
+
+		h.measureTimeout = timeout
+
+		return nil
+	}
+}
+
+// WithBackgroundInterval switches the container to the background mode: after Start the checks are run
+// every interval (or Config.Interval if set), and Measure and the handlers return the latest results
+// instead of running the checks on every call.
+func WithBackgroundInterval(interval time.Duration) Option {
+	return func(h *Health) error {
+		if interval <= 0 {
+			return errors.New("health checks background interval must be positive")
+		}
+
+		h.backgroundScheduler().interval = interval
+
+		return nil
+	}
+}
+
+// WithBackgroundJitter sets the maximum random delay added to every background run of a check,
+// so that replicas do not hit the same dependency at once. Defaults to 10% of the background interval.
+func WithBackgroundJitter(jitter time.Duration) Option {
+	return func(h *Health) error {
+		if jitter < 0 {
+			return errors.New("health checks background jitter must not be negative")
+		}
+
+		h.backgroundScheduler().jitter = jitter
+
+		return nil
+	}
+}
+
+// WithIETFFormat makes the readiness handler and the tags handlers always respond in the IETF health check
+// response format (application/health+json). Without it, the format is used only if the client accepts it.
+func WithIETFFormat() Option {
+	return func(h *Health) error {
+		h.ietfFormat = true
+
+		return nil
+	}
+}
+
+// WithServiceInfo sets the service details reported in the IETF health check response format.
+func WithServiceInfo(info ServiceInfo) Option {
+	return func(h *Health) error {
+		h.serviceInfo = info
+
+		return nil
+	}
+}
+
+// WithObserver adds an observer that is notified of the checks results, e.g. to export them as metrics.
+func WithObserver(o Observer) Option {
+	return func(h *Health) error {
+		if o == nil {
+			return errors.New("health checks observer must not be nil")
+		}
+
+		h.observers = append(h.observers, o)
+
+		return nil
+	}
+}
+
+// WithStatusCode sets the HTTP response code of the handlers for the status. By default, StatusUnavailable
+// is reported with 500 Internal Server Error and any other status with 200 OK.
+func WithStatusCode(s Status, code int) Option {
+	return func(h *Health) error {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("health status %q HTTP code %d is not valid", s, code)
+		}
+
+		if h.statusCodes == nil {
+			h.statusCodes = make(map[Status]int)
+		}
+
+		h.statusCodes[s] = code
+
+		return nil
+	}
+}
+
+// WithCacheTTL sets the period in which the latest result of a check is reused by Measure instead of running
+// the check again, unless the check sets its own Config.CacheTTL.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(h *Health) error {
+		if ttl <= 0 {
+			return errors.New("health checks cache TTL must be positive")
+		}
+
+		h.globalCacheTTL = ttl
+
+		return nil
+	}
+}
+
+// WithSystemMetrics sets the groups of the system metrics reported in the check response, e.g.
+// SystemGC|SystemMemory. None of the groups are reported by default, as they expose the process and host details.
+func WithSystemMetrics(groups SystemMetrics) Option {
+	return func(h *Health) error {
+		if groups&^SystemAll != 0 {
+			return fmt.Errorf("health system metrics groups %d are not supported", groups&^SystemAll)
+		}
+
+		h.systemMetrics = groups
+
+		return nil
+	}
+}
+
+// WithBuildInfo sets the build information reported in the check response, in the IETF response format and
+// to the observers implementing BuildObserver. The fields that are not set by the application are filled from
+// the build information embedded in the binary: the module version, and since Go 1.18 the VCS revision, time
+// and dirty flag.
+func WithBuildInfo(b BuildInfo) Option {
+	return func(h *Health) error {
+		b = readBuildInfo(b)
+		h.build = &b
+
+		return nil
+	}
+}
+
+// WithInfoContributors adds info contributors to newly instantiated health-container, see Health.Info.
+func WithInfoContributors(contributors ...InfoConfig) Option {
+	return func(h *Health) error {
+		for _, c := range contributors {
+			if err := h.RegisterInfo(c); err != nil {
+				return fmt.Errorf("could not register info contributor %q: %w", c.Name, err)
+			}
+		}
+
+		return nil
+	}
+}
@@ -1,7 +1,11 @@
 package health
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -15,20 +19,29 @@ func TestWithChecks(t *testing.T) {
 	require.NoError(t, err)
 	assert.Len(t, h1.checks, 0)
 
+	check := func(context.Context) error { return nil }
+
 	h2, err := New(WithChecks(Config{
-		Name: "foo",
+		Name:  "foo",
+		Check: check,
 	}, Config{
-		Name: "bar",
+		Name:  "bar",
+		Check: check,
 	}))
 	require.NoError(t, err)
 	assert.Len(t, h2.checks, 2)
 
 	_, err = New(WithChecks(Config{
-		Name: "foo",
+		Name:  "foo",
+		Check: check,
 	}, Config{
-		Name: "foo",
+		Name:  "foo",
+		Check: check,
 	}))
 	require.Error(t, err)
+
+	_, err = New(WithChecks(Config{Name: "foo"}))
+	require.EqualError(t, err, `could not register check "foo": health check must have a check func to be registered`)
 }
 
 type mockTracerProvider struct {
@@ -54,3 +67,28 @@ func TestWithTracerProvider(t *testing.T) {
 	assert.Same(t, tp, h2.tp)
 	assert.Equal(t, instrumentationName, h2.instrumentationName)
 }
+
+func TestWithStatusCode(t *testing.T) {
+	_, err := New(WithStatusCode(StatusUnavailable, 1000))
+	require.Error(t, err)
+
+	h, err := New(
+		WithStatusCode(StatusUnavailable, http.StatusServiceUnavailable),
+		WithStatusCode(StatusPartiallyAvailable, http.StatusMultiStatus),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusServiceUnavailable, h.statusCode(StatusUnavailable, handlerConfig{}))
+	assert.Equal(t, http.StatusMultiStatus, h.statusCode(StatusPartiallyAvailable, handlerConfig{}))
+	assert.Equal(t, http.StatusOK, h.statusCode(StatusOK, handlerConfig{}))
+	assert.Equal(t, http.StatusOK, h.statusCode(StatusTimeout, handlerConfig{}))
+
+	require.NoError(t, h.Register(Config{
+		Name:  "rabbitmq",
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}))
+
+	res := httptest.NewRecorder()
+	h.ReadinessHandlerFunc(res, httptest.NewRequest(http.MethodGet, "/status", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+}
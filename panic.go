@@ -0,0 +1,73 @@
+package health
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxStackFrames is the maximum number of the stack frames reported for a panicking check.
+const maxStackFrames = 16
+
+// panicError is returned for a check that panicked.
+type panicError struct {
+	value interface{}
+	stack string
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("health check panicked: %v", e.value)
+}
+
+// runCheckFunc runs the check function, the panic is recovered and returned as a panicError.
+func runCheckFunc(cs checkSpan, c Config) (out checkOutput) {
+	defer func() {
+		if v := recover(); v != nil {
+			out = checkOutput{err: &panicError{value: v, stack: trimStack(debug.Stack())}}
+		}
+	}()
+
+	if c.CheckWithResult != nil {
+		res, err := c.CheckWithResult(cs.ctx)
+		return checkOutput{result: res, err: err}
+	}
+
+	return checkOutput{err: c.Check(cs.ctx)}
+}
+
+// recordPanic adds the panic event to the check span.
+func (cs checkSpan) recordPanic(e *panicError) {
+	cs.span.AddEvent("panic", trace.WithAttributes(
+		attribute.String("panic.value", fmt.Sprint(e.value)),
+		attribute.String("panic.stack", e.stack),
+	))
+}
+
+// trimStack removes the goroutine header and the frames of the panic handling from the stack trace,
+// and keeps at most maxStackFrames frames.
+func trimStack(stack []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(stack)), "\n")
+
+	// every frame is a function line followed by a file line
+	start := 1
+	for i := 1; i < len(lines); i += 2 {
+		if strings.HasPrefix(lines[i], "panic(") {
+			start = i + 2
+			break
+		}
+	}
+
+	if start >= len(lines) {
+		start = 1
+	}
+
+	lines = lines[start:]
+	if len(lines) > 2*maxStackFrames {
+		lines = lines[:2*maxStackFrames]
+	}
+
+	return strings.Join(lines, "\n")
+}
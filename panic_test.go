@@ -0,0 +1,59 @@
+package health
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type nilClient struct{ conn *strings.Builder }
+
+func (c *nilClient) ping() error {
+	c.conn.WriteString("ping")
+	return nil
+}
+
+func TestMeasureRecoversPanics(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+
+	var client *nilClient
+	h, err := New(WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(spans)), "test"), WithChecks(Config{
+		Name:  "custom",
+		Check: func(context.Context) error { return client.ping() },
+	}, Config{
+		Name:      "value",
+		SkipOnErr: true,
+		Check:     func(context.Context) error { panic("unexpected state") },
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, c.Status)
+
+	custom := c.Services["custom"]
+	assert.False(t, custom.IsOk)
+	assert.True(t, custom.Panicked)
+	assert.Contains(t, custom.Message, "health check panicked: runtime error: invalid memory address or nil pointer dereference")
+	assert.True(t, strings.HasPrefix(custom.Stack, "github.com/mhfinans/health-go.(*nilClient).ping"), custom.Stack)
+	assert.LessOrEqual(t, strings.Count(custom.Stack, "\n"), 2*maxStackFrames-1)
+
+	value := c.Services["value"]
+	assert.True(t, value.Panicked)
+	assert.True(t, value.Skippable)
+	assert.Equal(t, "health check panicked: unexpected state", value.Message)
+
+	var events int
+	for _, s := range spans.Ended() {
+		for _, e := range s.Events() {
+			if e.Name == "panic" {
+				events++
+			}
+		}
+	}
+	assert.Equal(t, 2, events)
+}
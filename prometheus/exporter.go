@@ -0,0 +1,262 @@
+package prometheus
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/mhfinans/health-go"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+const defaultNamespace = "health"
+
+// allStatuses are the statuses the status gauge is exported for.
+var allStatuses = []health.Status{
+	health.StatusOK,
+	health.StatusPartiallyAvailable,
+	health.StatusUnavailable,
+	health.StatusTimeout,
+}
+
+// allBreakerStates are the states the circuit breaker gauge is exported for.
+var allBreakerStates = []health.BreakerState{
+	health.BreakerClosed,
+	health.BreakerOpen,
+	health.BreakerHalfOpen,
+}
+
+// Config is the Prometheus exporter configuration settings container.
+type Config struct {
+	// Namespace is the namespace of the exported metrics.
+	// If not set - "health"
+	Namespace string
+	// Buckets are the buckets of the check duration histogram, in seconds.
+	// If not set - prometheus.DefBuckets
+	Buckets []float64
+	// ConstLabels are the labels added to every exported metric.
+	ConstLabels prom.Labels
+}
+
+// Exporter exports the health checks results as Prometheus metrics. It is both a health.Observer,
+// to be passed to health.WithObserver, and a prometheus.Collector, to be registered in a Prometheus registry.
+type Exporter struct {
+	up       *prom.GaugeVec
+	duration *prom.HistogramVec
+	failures *prom.CounterVec
+	timeouts *prom.CounterVec
+	panics   *prom.CounterVec
+	breakers *prom.GaugeVec
+	build    *prom.GaugeVec
+	observed *prom.GaugeVec
+	status   *prom.GaugeVec
+
+	mu sync.Mutex
+	// units are the units of the latest observed values of the checks, the series with a previous unit is deleted.
+	units map[string]string
+}
+
+// New creates new Prometheus exporter of the following metrics:
+// - {namespace}_check_up - 1 if the check is reported as ok, 0 otherwise
+// - {namespace}_check_duration_seconds - histogram of the checks durations
+// - {namespace}_check_failures_total - number of the checks failures, including the failures below the threshold
+// - {namespace}_check_timeouts_total - number of the checks timeouts
+// - {namespace}_check_panics_total - number of the checks panics
+// - {namespace}_check_circuit_breaker - 1 for the current circuit breaker state of the check, 0 for the others
+// - {namespace}_check_observed_value - the latest numeric value observed by the check, see health.Config.CheckWithResult
+// - {namespace}_build_info - always 1, the build information of the service is in the labels, see health.WithBuildInfo
+// - {namespace}_status - 1 for the current overall status, 0 for the others
+func New(config Config) *Exporter {
+	if config.Namespace == "" {
+		config.Namespace = defaultNamespace
+	}
+
+	if config.Buckets == nil {
+		config.Buckets = prom.DefBuckets
+	}
+
+	return &Exporter{
+		up: prom.NewGaugeVec(prom.GaugeOpts{
+			Namespace:   config.Namespace,
+			Name:        "check_up",
+			Help:        "Whether the health check is reported as ok (1) or failed (0).",
+			ConstLabels: config.ConstLabels,
+		}, []string{"check"}),
+		duration: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace:   config.Namespace,
+			Name:        "check_duration_seconds",
+			Help:        "Duration of the health checks runs.",
+			Buckets:     config.Buckets,
+			ConstLabels: config.ConstLabels,
+		}, []string{"check"}),
+		failures: prom.NewCounterVec(prom.CounterOpts{
+			Namespace:   config.Namespace,
+			Name:        "check_failures_total",
+			Help:        "Total number of the health checks failures.",
+			ConstLabels: config.ConstLabels,
+		}, []string{"check"}),
+		timeouts: prom.NewCounterVec(prom.CounterOpts{
+			Namespace:   config.Namespace,
+			Name:        "check_timeouts_total",
+			Help:        "Total number of the health checks timeouts.",
+			ConstLabels: config.ConstLabels,
+		}, []string{"check"}),
+		panics: prom.NewCounterVec(prom.CounterOpts{
+			Namespace:   config.Namespace,
+			Name:        "check_panics_total",
+			Help:        "Total number of the health checks panics.",
+			ConstLabels: config.ConstLabels,
+		}, []string{"check"}),
+		breakers: prom.NewGaugeVec(prom.GaugeOpts{
+			Namespace:   config.Namespace,
+			Name:        "check_circuit_breaker",
+			Help:        "Circuit breaker state of the health check, 1 for the current state and 0 for the others.",
+			ConstLabels: config.ConstLabels,
+		}, []string{"check", "state"}),
+		observed: prom.NewGaugeVec(prom.GaugeOpts{
+			Namespace:   config.Namespace,
+			Name:        "check_observed_value",
+			Help:        "Latest value observed by the health check.",
+			ConstLabels: config.ConstLabels,
+		}, []string{"check", "unit"}),
+		build: prom.NewGaugeVec(prom.GaugeOpts{
+			Namespace:   config.Namespace,
+			Name:        "build_info",
+			Help:        "Build information of the service, always 1.",
+			ConstLabels: config.ConstLabels,
+		}, []string{"version", "release", "module_version", "revision", "dirty", "go_version"}),
+		status: prom.NewGaugeVec(prom.GaugeOpts{
+			Namespace:   config.Namespace,
+			Name:        "status",
+			Help:        "Overall health status, 1 for the current status and 0 for the others.",
+			ConstLabels: config.ConstLabels,
+		}, []string{"status"}),
+		units: make(map[string]string),
+	}
+}
+
+// ObserveCheck implements health.Observer.
+func (e *Exporter) ObserveCheck(name string, s health.ServiceStatus) {
+	up := 0.0
+	if s.IsOk {
+		up = 1
+	}
+
+	e.up.WithLabelValues(name).Set(up)
+	e.duration.WithLabelValues(name).Observe(s.Duration.Seconds())
+
+	// the counters are initialised, so that they are exported before the first failure
+	failures := e.failures.WithLabelValues(name)
+	timeouts := e.timeouts.WithLabelValues(name)
+	panics := e.panics.WithLabelValues(name)
+
+	if s.Failed() {
+		failures.Inc()
+	}
+
+	if s.TimedOut && !s.ShortCircuited {
+		timeouts.Inc()
+	}
+
+	if s.Panicked {
+		panics.Inc()
+	}
+
+	if v, ok := s.ObservedFloat(); ok {
+		e.observeValue(name, v, s.ObservedUnit)
+	}
+
+	if s.CircuitBreaker != "" {
+		for _, state := range allBreakerStates {
+			value := 0.0
+			if state == s.CircuitBreaker {
+				value = 1
+			}
+
+			e.breakers.WithLabelValues(name, string(state)).Set(value)
+		}
+	}
+}
+
+func (e *Exporter) observeValue(name string, v float64, unit string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if prev, ok := e.units[name]; ok && prev != unit {
+		e.observed.DeleteLabelValues(name, prev)
+	}
+
+	e.units[name] = unit
+	e.observed.WithLabelValues(name, unit).Set(v)
+}
+
+// ForgetCheck implements health.CheckForgetter, the series of the check are deleted.
+func (e *Exporter) ForgetCheck(name string) {
+	e.up.DeleteLabelValues(name)
+	e.duration.DeleteLabelValues(name)
+	e.failures.DeleteLabelValues(name)
+	e.timeouts.DeleteLabelValues(name)
+	e.panics.DeleteLabelValues(name)
+
+	for _, state := range allBreakerStates {
+		e.breakers.DeleteLabelValues(name, string(state))
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if unit, ok := e.units[name]; ok {
+		e.observed.DeleteLabelValues(name, unit)
+		delete(e.units, name)
+	}
+}
+
+// ObserveBuild implements health.BuildObserver.
+func (e *Exporter) ObserveBuild(b health.BuildInfo) {
+	e.build.Reset()
+	e.build.WithLabelValues(b.Version, b.Release, b.ModuleVersion, b.Revision, strconv.FormatBool(b.Dirty), b.GoVersion).Set(1)
+}
+
+// ObserveStatus implements health.Observer.
+func (e *Exporter) ObserveStatus(s health.Status) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, status := range allStatuses {
+		value := 0.0
+		if status == s {
+			value = 1
+		}
+
+		e.status.WithLabelValues(string(status)).Set(value)
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (e *Exporter) Describe(ch chan<- *prom.Desc) {
+	e.up.Describe(ch)
+	e.duration.Describe(ch)
+	e.failures.Describe(ch)
+	e.timeouts.Describe(ch)
+	e.panics.Describe(ch)
+	e.breakers.Describe(ch)
+	e.build.Describe(ch)
+	e.observed.Describe(ch)
+	e.status.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (e *Exporter) Collect(ch chan<- prom.Metric) {
+	e.up.Collect(ch)
+	e.duration.Collect(ch)
+	e.failures.Collect(ch)
+	e.timeouts.Collect(ch)
+	e.panics.Collect(ch)
+	e.breakers.Collect(ch)
+	e.build.Collect(ch)
+	e.observed.Collect(ch)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.status.Collect(ch)
+}
@@ -0,0 +1,211 @@
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mhfinans/health-go"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExporter(t *testing.T) {
+	exp := New(Config{ConstLabels: prom.Labels{"app": "test"}})
+
+	h, err := health.New(health.WithObserver(exp), health.WithChecks(health.Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}, health.Config{
+		Name:      "rabbitmq",
+		SkipOnErr: true,
+		Check:     func(context.Context) error { return errors.New("rabbitmq is down") },
+	}, health.Config{
+		Name:      "broken",
+		SkipOnErr: true,
+		Check:     func(context.Context) error { panic("nil client") },
+	}, health.Config{
+		Name:      "snail",
+		SkipOnErr: true,
+		Timeout:   10 * time.Millisecond,
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}))
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+	h.Measure(context.Background())
+
+	expected := `
+# HELP health_check_failures_total Total number of the health checks failures.
+# TYPE health_check_failures_total counter
+health_check_failures_total{app="test",check="broken"} 2
+health_check_failures_total{app="test",check="postgres"} 0
+health_check_failures_total{app="test",check="rabbitmq"} 2
+health_check_failures_total{app="test",check="snail"} 2
+# HELP health_check_panics_total Total number of the health checks panics.
+# TYPE health_check_panics_total counter
+health_check_panics_total{app="test",check="broken"} 2
+health_check_panics_total{app="test",check="postgres"} 0
+health_check_panics_total{app="test",check="rabbitmq"} 0
+health_check_panics_total{app="test",check="snail"} 0
+# HELP health_check_timeouts_total Total number of the health checks timeouts.
+# TYPE health_check_timeouts_total counter
+health_check_timeouts_total{app="test",check="broken"} 0
+health_check_timeouts_total{app="test",check="postgres"} 0
+health_check_timeouts_total{app="test",check="rabbitmq"} 0
+health_check_timeouts_total{app="test",check="snail"} 2
+# HELP health_check_up Whether the health check is reported as ok (1) or failed (0).
+# TYPE health_check_up gauge
+health_check_up{app="test",check="broken"} 0
+health_check_up{app="test",check="postgres"} 1
+health_check_up{app="test",check="rabbitmq"} 0
+health_check_up{app="test",check="snail"} 0
+# HELP health_status Overall health status, 1 for the current status and 0 for the others.
+# TYPE health_status gauge
+health_status{app="test",status="OK"} 0
+health_status{app="test",status="Partially Available"} 1
+health_status{app="test",status="Timeout during health check"} 0
+health_status{app="test",status="Unavailable"} 0
+`
+
+	err = testutil.CollectAndCompare(exp, strings.NewReader(expected),
+		"health_check_failures_total", "health_check_panics_total", "health_check_timeouts_total", "health_check_up", "health_status")
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, testutil.CollectAndCount(exp, "health_check_duration_seconds"))
+
+	reg := prom.NewPedanticRegistry()
+	require.NoError(t, reg.Register(exp))
+}
+
+func TestExporterCircuitBreaker(t *testing.T) {
+	exp := New(Config{})
+
+	h, err := health.New(health.WithObserver(exp), health.WithChecks(health.Config{
+		Name:           "mongo",
+		CircuitBreaker: &health.CircuitBreaker{FailureThreshold: 1, CoolDown: time.Minute},
+		Check:          func(context.Context) error { return errors.New("mongo is down") },
+	}))
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+	h.Measure(context.Background())
+
+	expected := `
+# HELP health_check_circuit_breaker Circuit breaker state of the health check, 1 for the current state and 0 for the others.
+# TYPE health_check_circuit_breaker gauge
+health_check_circuit_breaker{check="mongo",state="closed"} 0
+health_check_circuit_breaker{check="mongo",state="half-open"} 0
+health_check_circuit_breaker{check="mongo",state="open"} 1
+# HELP health_check_failures_total Total number of the health checks failures.
+# TYPE health_check_failures_total counter
+health_check_failures_total{check="mongo"} 1
+`
+
+	err = testutil.CollectAndCompare(exp, strings.NewReader(expected),
+		"health_check_circuit_breaker", "health_check_failures_total")
+	require.NoError(t, err)
+}
+
+func TestExporterBuildInfo(t *testing.T) {
+	exp := New(Config{})
+
+	_, err := health.New(health.WithObserver(exp), health.WithBuildInfo(health.BuildInfo{
+		Version:   "1.2.3",
+		Release:   "2022-08-01.1",
+		Revision:  "abc123",
+		GoVersion: "go1.18",
+	}))
+	require.NoError(t, err)
+
+	expected := `
+# HELP health_build_info Build information of the service, always 1.
+# TYPE health_build_info gauge
+health_build_info{dirty="false",go_version="go1.18",module_version="",release="2022-08-01.1",revision="abc123",version="1.2.3"} 1
+`
+
+	err = testutil.CollectAndCompare(exp, strings.NewReader(expected), "health_build_info")
+	require.NoError(t, err)
+}
+
+func TestExporterObservedValue(t *testing.T) {
+	exp := New(Config{})
+
+	var runs int32
+
+	h, err := health.New(health.WithObserver(exp), health.WithChecks(health.Config{
+		Name: "kafka",
+		CheckWithResult: func(context.Context) (health.Result, error) {
+			if atomic.AddInt32(&runs, 1) > 1 {
+				return health.Result{ObservedValue: 2, ObservedUnit: "s"}, nil
+			}
+			return health.Result{ObservedValue: 25 * time.Millisecond, ObservedUnit: "ms"}, nil
+		},
+	}, health.Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+
+	expected := `
+# HELP health_check_observed_value Latest value observed by the health check.
+# TYPE health_check_observed_value gauge
+health_check_observed_value{check="kafka",unit="ms"} 25
+`
+
+	err = testutil.CollectAndCompare(exp, strings.NewReader(expected), "health_check_observed_value")
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+
+	expected = `
+# HELP health_check_observed_value Latest value observed by the health check.
+# TYPE health_check_observed_value gauge
+health_check_observed_value{check="kafka",unit="s"} 2
+`
+
+	err = testutil.CollectAndCompare(exp, strings.NewReader(expected), "health_check_observed_value")
+	require.NoError(t, err, "series with the previous unit should be deleted")
+}
+
+func TestExporterForgetCheck(t *testing.T) {
+	exp := New(Config{})
+
+	h, err := health.New(health.WithObserver(exp), health.WithChecks(health.Config{
+		Name:           "mongo",
+		CircuitBreaker: &health.CircuitBreaker{FailureThreshold: 1, CoolDown: time.Minute},
+		CheckWithResult: func(context.Context) (health.Result, error) {
+			return health.Result{ObservedValue: 3, ObservedUnit: "connections"}, nil
+		},
+	}, health.Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	h.Measure(context.Background())
+	require.NoError(t, h.Unregister("mongo"))
+
+	expected := `
+# HELP health_check_failures_total Total number of the health checks failures.
+# TYPE health_check_failures_total counter
+health_check_failures_total{check="postgres"} 0
+# HELP health_check_up Whether the health check is reported as ok (1) or failed (0).
+# TYPE health_check_up gauge
+health_check_up{check="postgres"} 1
+`
+
+	err = testutil.CollectAndCompare(exp, strings.NewReader(expected),
+		"health_check_up", "health_check_failures_total", "health_check_circuit_breaker", "health_check_observed_value")
+	require.NoError(t, err, "series of the unregistered check should be deleted")
+}
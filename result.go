@@ -0,0 +1,74 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+type (
+	// CheckWithResultFunc is the func which executes the check and returns its structured result.
+	CheckWithResultFunc func(context.Context) (Result, error)
+
+	// Result is the structured result of a check, see Config.CheckWithResult.
+	// It is reported along with the check error, if any.
+	Result struct {
+		// Details are the check details, e.g. the version of the dependency. They must be JSON serializable.
+		Details interface{}
+		// ObservedValue is the value measured by the check, e.g. the replication lag.
+		// The numeric values and the durations are exported as metrics.
+		ObservedValue interface{}
+		// ObservedUnit is the unit of the observed value, e.g. "ms".
+		ObservedUnit string
+		// Warning is the warning message of a check that passed in a degraded state. The check is reported as ok,
+		// while the overall status is partially available, unless the check is informational.
+		Warning string
+	}
+)
+
+// checkOutput is the outcome of a check func.
+type checkOutput struct {
+	result Result
+	err    error
+}
+
+// apply fills the status with the check result.
+func (r Result) apply(s *ServiceStatus) {
+	s.Details = r.Details
+	s.ObservedValue = r.ObservedValue
+	s.ObservedUnit = r.ObservedUnit
+
+	if s.IsOk {
+		s.Warning = r.Warning
+	}
+}
+
+// ObservedFloat returns the observed value as a float, ok is false if it is not set or it is not numeric.
+// The durations are returned in the observed unit if it is "s" or "ms", and in seconds otherwise.
+func (s ServiceStatus) ObservedFloat() (float64, bool) {
+	switch v := s.ObservedValue.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case time.Duration:
+		if s.ObservedUnit == "ms" {
+			return float64(v) / float64(time.Millisecond), true
+		}
+
+		return v.Seconds(), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,105 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckWithResult(t *testing.T) {
+	lag := 2 * time.Second
+
+	h, err := New(WithChecks(Config{
+		Name: "postgres",
+		CheckWithResult: func(context.Context) (Result, error) {
+			return Result{
+				Details:       map[string]interface{}{"version": "PostgreSQL 14.4"},
+				ObservedValue: lag,
+				ObservedUnit:  "s",
+			}, nil
+		},
+	}, Config{
+		Name:  "redis",
+		Check: func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status)
+
+	pg := c.Services["postgres"]
+	assert.True(t, pg.IsOk)
+	assert.Equal(t, map[string]interface{}{"version": "PostgreSQL 14.4"}, pg.Details)
+	assert.Equal(t, lag, pg.ObservedValue)
+
+	v, ok := pg.ObservedFloat()
+	require.True(t, ok)
+	assert.Equal(t, 2.0, v)
+
+	_, ok = c.Services["redis"].ObservedFloat()
+	assert.False(t, ok)
+
+	data, err := json.Marshal(c.Services["redis"])
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "details", "existing checks output should not change")
+
+	ietf := NewIETFResponse(c, ServiceInfo{})
+	require.Len(t, ietf.Checks["postgres:observedValue"], 1)
+	assert.Equal(t, "s", ietf.Checks["postgres:observedValue"][0].ObservedUnit)
+	assert.NotContains(t, ietf.Checks, "redis:observedValue")
+}
+
+func TestCheckWithResultWarning(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name: "replica",
+		CheckWithResult: func(context.Context) (Result, error) {
+			return Result{ObservedValue: 1500, ObservedUnit: "ms", Warning: "replication lag is above 1s"}, nil
+		},
+	}, Config{
+		Name:        "cache",
+		Criticality: CriticalityInformational,
+		CheckWithResult: func(context.Context) (Result, error) {
+			return Result{Warning: "cache hit ratio is low"}, nil
+		},
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusPartiallyAvailable, c.Status, "warning should degrade the status")
+	assert.True(t, bool(c.IsOK))
+	assert.True(t, c.Services["replica"].IsOk)
+	assert.Equal(t, "replication lag is above 1s", c.Services["replica"].Warning)
+
+	ietf := NewIETFResponse(c, ServiceInfo{})
+	assert.Equal(t, IETFStatusWarn, ietf.Status)
+	assert.Equal(t, IETFStatusWarn, ietf.Checks["replica:responseTime"][0].Status)
+	assert.Equal(t, "replication lag is above 1s", ietf.Checks["replica:responseTime"][0].Output)
+
+	require.NoError(t, h.Unregister("replica"))
+	c = h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status, "informational warning should not affect the status")
+}
+
+func TestCheckWithResultError(t *testing.T) {
+	h, err := New(WithChecks(Config{
+		Name: "kafka",
+		CheckWithResult: func(context.Context) (Result, error) {
+			return Result{ObservedValue: 3.5, ObservedUnit: "s", Warning: "slow"}, errors.New(checkErr)
+		},
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, c.Status)
+
+	kafka := c.Services["kafka"]
+	assert.False(t, kafka.IsOk)
+	assert.Equal(t, checkErr, kafka.Message)
+	assert.Equal(t, 3.5, kafka.ObservedValue, "result should be reported along with the error")
+	assert.Empty(t, kafka.Warning, "warning should be reported only for the passed checks")
+}
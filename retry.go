@@ -0,0 +1,76 @@
+package health
+
+import (
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RetryPolicy defines how a failed check is retried within a single run. All the attempts share the check timeout,
+// no retry is started if its backoff does not fit in the remaining time.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of the check attempts, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// Multiplier is the factor the backoff is multiplied by after every retry. If not set, the backoff is constant.
+	Multiplier float64
+	// Jitter is the maximum random delay added to every backoff.
+	Jitter time.Duration
+}
+
+func (p *RetryPolicy) validate() error {
+	if p.MaxAttempts < 0 || p.InitialBackoff < 0 || p.Multiplier < 0 || p.Jitter < 0 {
+		return errors.New("health check retry policy must not be negative")
+	}
+
+	return nil
+}
+
+// retryCheck runs the check until it succeeds, the attempts are exhausted or the check context is done.
+// The timeouts and the panics are not retried.
+func (h *Health) retryCheck(cs checkSpan, c Config) ServiceStatus {
+	p := c.Retry
+	if p == nil || p.MaxAttempts <= 1 {
+		res := h.execCheck(cs, c)
+		res.Attempts = 1
+		return res
+	}
+
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+
+	backoff := p.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		res := h.execCheck(cs, c)
+		res.Attempts = attempt
+
+		if res.IsOk || res.TimedOut || res.Panicked || attempt >= p.MaxAttempts || cs.ctx.Err() != nil {
+			return res
+		}
+
+		delay := backoff + randDuration(p.Jitter)
+		if deadline, ok := cs.ctx.Deadline(); ok && time.Until(deadline) <= delay {
+			return res
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-cs.ctx.Done():
+			timer.Stop()
+			return res
+		case <-timer.C:
+		}
+
+		cs.span.AddEvent("retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt+1),
+			attribute.String("error", res.Message),
+		))
+
+		backoff = time.Duration(float64(backoff) * multiplier)
+	}
+}
@@ -0,0 +1,89 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var calls int32
+
+	h, err := New(WithChecks(Config{
+		Name: "flaky",
+		Retry: &RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			Multiplier:     2,
+			Jitter:         time.Millisecond,
+		},
+		Check: func(context.Context) error {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				return errors.New(checkErr)
+			}
+			return nil
+		},
+	}, Config{
+		Name:  "single",
+		Check: func(context.Context) error { return nil },
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status)
+	assert.True(t, c.Services["flaky"].IsOk)
+	assert.Equal(t, 3, c.Services["flaky"].Attempts)
+	assert.Equal(t, 0, c.Services["flaky"].ConsecutiveFailures, "retried failures should not count as failures")
+	assert.Equal(t, 1, c.Services["single"].Attempts)
+
+	atomic.StoreInt32(&calls, -10)
+
+	c = h.Measure(context.Background())
+	assert.False(t, c.Services["flaky"].IsOk)
+	assert.Equal(t, 3, c.Services["flaky"].Attempts)
+	assert.Equal(t, checkErr, c.Services["flaky"].Message)
+	assert.Equal(t, int32(-7), atomic.LoadInt32(&calls))
+
+	err = h.Register(Config{Name: "negative", Retry: &RetryPolicy{MaxAttempts: -1}})
+	require.Error(t, err)
+}
+
+func TestRetryPolicyWithinTimeout(t *testing.T) {
+	var calls int32
+
+	h, err := New(WithChecks(Config{
+		Name:    "slow-backoff",
+		Timeout: 50 * time.Millisecond,
+		Retry:   &RetryPolicy{MaxAttempts: 5, InitialBackoff: 20 * time.Millisecond, Multiplier: 2},
+		Check: func(context.Context) error {
+			atomic.AddInt32(&calls, 1)
+			return errors.New(checkErr)
+		},
+	}, Config{
+		Name:    "slow-check",
+		Timeout: 50 * time.Millisecond,
+		Retry:   &RetryPolicy{MaxAttempts: 5},
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}))
+	require.NoError(t, err)
+
+	startedAt := time.Now()
+	c := h.Measure(context.Background())
+	assert.Less(t, time.Since(startedAt), 100*time.Millisecond)
+
+	// the second backoff of 40ms does not fit in the remaining timeout
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, 2, c.Services["slow-backoff"].Attempts)
+	assert.Equal(t, checkErr, c.Services["slow-backoff"].Message)
+
+	assert.True(t, c.Services["slow-check"].TimedOut)
+	assert.Equal(t, 1, c.Services["slow-check"].Attempts, "timeouts should not be retried")
+}
@@ -0,0 +1,220 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// scheduler runs the registered checks periodically and keeps their latest results.
+type scheduler struct {
+	interval time.Duration
+	jitter   time.Duration
+
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	ctx     context.Context
+	cancel  context.CancelFunc
+	loops   map[string]context.CancelFunc
+	results map[string]ServiceStatus
+}
+
+func (h *Health) backgroundScheduler() *scheduler {
+	if h.scheduler == nil {
+		h.scheduler = &scheduler{jitter: -1}
+	}
+
+	return h.scheduler
+}
+
+// Start starts running the registered checks in background, every check on its own interval.
+// Checks registered after Start are scheduled as soon as they are registered.
+// The container must be created with WithBackgroundInterval, Stop must be called to release the background goroutines.
+// Cancelling ctx stops the background checks the same way as Stop.
+func (h *Health) Start(ctx context.Context) error {
+	if h.scheduler == nil {
+		return errors.New("health checks background interval is not configured")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.scheduler
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.mu.Unlock()
+		return errors.New("health checks are already running in background")
+	}
+
+	// the loops are cancelled by stop only, so that a cancelled run is never taken for a failure
+	s.ctx, s.cancel = context.WithCancel(detachedContext{ctx})
+	s.loops = make(map[string]context.CancelFunc, len(h.checks))
+	s.results = make(map[string]ServiceStatus, len(h.checks))
+
+	s.wg.Add(1)
+	go s.watch(ctx, s.ctx)
+	s.mu.Unlock()
+
+	for _, c := range h.checks {
+		s.schedule(h, c, h.states[c.Name])
+	}
+
+	return nil
+}
+
+// Stop stops the background checks started with Start and waits for the running ones to return.
+// After Stop, Measure runs the checks inline again.
+func (h *Health) Stop() {
+	s := h.scheduler
+	if s == nil {
+		return
+	}
+
+	s.stop(nil)
+	s.wg.Wait()
+}
+
+// watch stops the scheduler when the context passed to Start is done.
+func (s *scheduler) watch(parent, ctx context.Context) {
+	defer s.wg.Done()
+
+	select {
+	case <-parent.Done():
+		s.stop(ctx)
+	case <-ctx.Done():
+	}
+}
+
+// stop cancels the background loops and drops their results. If ctx is not nil,
+// the scheduler is stopped only if it is still running with ctx.
+func (s *scheduler) stop(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel == nil || ctx != nil && s.ctx != ctx {
+		return
+	}
+
+	s.cancel()
+	s.ctx, s.cancel = nil, nil
+	s.loops = nil
+	s.results = nil
+}
+
+// schedule starts the background loop of the check if the scheduler is running.
+func (s *scheduler) schedule(h *Health, c Config, st *checkState) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel == nil {
+		return
+	}
+
+	interval := c.Interval
+	if interval == 0 {
+		interval = s.interval
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.loops[c.Name] = cancel
+
+	s.wg.Add(1)
+	go s.loop(ctx, h, c, st, interval)
+}
+
+// unschedule stops the background loop of the check and drops its latest result.
+func (s *scheduler) unschedule(name string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cancel, ok := s.loops[name]; ok {
+		cancel()
+		delete(s.loops, name)
+		delete(s.results, name)
+	}
+}
+
+func (s *scheduler) loop(ctx context.Context, h *Health, c Config, st *checkState, interval time.Duration) {
+	defer s.wg.Done()
+
+	tracer := h.tp.Tracer(h.instrumentationName)
+
+	// the first run is delayed by jitter only, so that replicas started together spread their checks
+	timer := time.NewTimer(randDuration(s.jitter))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		res := h.runDependentCheck(ctx, tracer, registeredCheck{config: c, state: st}, h.dependencyResult)
+
+		// the run cancelled by Stop, Unregister or Replace is neither kept nor observed
+		s.mu.Lock()
+		cancelled := ctx.Err() != nil
+		if !cancelled {
+			s.results[c.Name] = res
+		}
+		s.mu.Unlock()
+
+		if cancelled {
+			return
+		}
+
+		h.observeStatus()
+
+		timer.Reset(interval + randDuration(s.jitter))
+	}
+}
+
+// snapshot returns a copy of the latest results, ok is false if the scheduler is not running.
+func (s *scheduler) snapshot() (map[string]ServiceStatus, bool) {
+	if s == nil {
+		return nil, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancel == nil {
+		return nil, false
+	}
+
+	results := make(map[string]ServiceStatus, len(s.results))
+	for name, res := range s.results {
+		results[name] = res
+	}
+
+	return results, true
+}
+
+var (
+	randMu sync.Mutex
+	// the global source is deterministic before go1.20, replicas must not share the same jitter
+	randSrc = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// randDuration returns a random duration in [0, max).
+func randDuration(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+
+	randMu.Lock()
+	defer randMu.Unlock()
+
+	return time.Duration(randSrc.Int63n(int64(max)))
+}
@@ -0,0 +1,188 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartWithoutBackgroundInterval(t *testing.T) {
+	h, err := New()
+	require.NoError(t, err)
+
+	err = h.Start(context.Background())
+	require.Error(t, err)
+
+	// Stop must be safe to call when background mode is not configured
+	h.Stop()
+}
+
+func TestWithBackgroundJitterWithoutInterval(t *testing.T) {
+	_, err := New(WithBackgroundJitter(time.Second))
+	require.Error(t, err)
+
+	_, err = New(WithBackgroundInterval(0))
+	require.Error(t, err)
+
+	h, err := New(WithBackgroundInterval(time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, 100*time.Millisecond, h.scheduler.jitter)
+}
+
+func TestBackgroundMeasure(t *testing.T) {
+	var calls int32
+
+	h, err := New(
+		WithBackgroundInterval(10*time.Millisecond),
+		WithBackgroundJitter(0),
+		WithChecks(Config{
+			Name: "counter",
+			Check: func(context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				return nil
+			},
+		}, Config{
+			Name:      "failing",
+			SkipOnErr: true,
+			Check:     func(context.Context) error { return errors.New(checkErr) },
+		}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, h.Start(context.Background()))
+	defer h.Stop()
+
+	require.Error(t, h.Start(context.Background()), "second start should return an error")
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) >= 3
+	}, time.Second, 5*time.Millisecond)
+
+	before := atomic.LoadInt32(&calls)
+	c := h.Measure(context.Background())
+	assert.Equal(t, before, atomic.LoadInt32(&calls), "measure in background mode should not run the checks")
+
+	assert.Equal(t, StatusPartiallyAvailable, c.Status)
+	assert.True(t, c.Services["counter"].IsOk)
+	assert.Equal(t, checkErr, c.Services["failing"].Message)
+
+	// checks registered after Start are scheduled as well
+	require.NoError(t, h.Register(Config{
+		Name:  "late",
+		Check: func(context.Context) error { return nil },
+	}))
+
+	require.Eventually(t, func() bool {
+		return h.Measure(context.Background()).Services["late"].IsOk
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, h.Unregister("failing"))
+	require.NoError(t, h.Replace(Config{
+		Name:  "late",
+		Check: func(context.Context) error { return errors.New(checkErr) },
+	}))
+
+	require.Eventually(t, func() bool {
+		c := h.Measure(context.Background())
+		return len(c.Services) == 2 && c.Services["late"].Message == checkErr
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestBackgroundMeasureNotRunYet(t *testing.T) {
+	h, err := New(
+		WithBackgroundInterval(time.Hour),
+		WithBackgroundJitter(time.Hour),
+		WithChecks(Config{
+			Name:  "slow-start",
+			Check: func(context.Context) error { return nil },
+		}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, h.Start(context.Background()))
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, c.Status)
+	assert.False(t, c.Services["slow-start"].IsOk)
+
+	h.Stop()
+
+	// after Stop the checks are run inline again
+	c = h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status)
+}
+
+func TestBackgroundStopDuringRun(t *testing.T) {
+	started := make(chan struct{})
+
+	h, err := New(
+		WithBackgroundInterval(time.Hour),
+		WithBackgroundJitter(0),
+		WithChecks(Config{
+			Name:           "postgres",
+			CircuitBreaker: &CircuitBreaker{FailureThreshold: 1, CoolDown: time.Hour},
+			Check: func(ctx context.Context) error {
+				close(started)
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}),
+	)
+	require.NoError(t, err)
+
+	var events []Event
+	h.Subscribe(func(e Event) { events = append(events, e) })
+
+	require.NoError(t, h.Start(context.Background()))
+	<-started
+	h.Stop()
+
+	assert.Empty(t, events, "cancelled run should not publish status changes")
+
+	res, ok := h.registered()[0].state.lastResult()
+	assert.False(t, ok, "cancelled run should not be recorded, got %+v", res)
+	assert.Equal(t, StatusOK, h.overallStatus())
+}
+
+func TestBackgroundStartContextCancelled(t *testing.T) {
+	var calls int32
+
+	h, err := New(
+		WithBackgroundInterval(time.Hour),
+		WithBackgroundJitter(0),
+		WithChecks(Config{
+			Name: "counter",
+			Check: func(context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				return nil
+			},
+		}),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, h.Start(ctx))
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&calls) == 1
+	}, time.Second, 5*time.Millisecond)
+
+	cancel()
+
+	require.Eventually(t, func() bool {
+		_, ok := h.scheduler.snapshot()
+		return !ok
+	}, time.Second, 5*time.Millisecond, "cancelling the start context should stop the scheduler")
+
+	// the checks are run inline again and the scheduler can be started again
+	h.Measure(context.Background())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	require.NoError(t, h.Start(context.Background()))
+	h.Stop()
+}
@@ -0,0 +1,145 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Startup represents the startup check response.
+type Startup struct {
+	// IsOK tells if the service has started.
+	IsOK IsOK `json:"is_service_ok"`
+	// Status is the startup status.
+	Status Status `json:"status"`
+	// Services holds the startup checks along with their messages, it is empty once the service has started.
+	Services map[string]ServiceStatus `json:"service,omitempty"`
+	// PendingTasks are the names of the startup tasks that are not completed yet.
+	PendingTasks []string `json:"pending_tasks,omitempty"`
+}
+
+// startup is the one-time startup completion latch.
+type startup struct {
+	mu      sync.Mutex
+	started bool
+	tasks   map[string]bool
+}
+
+// AddStartupTask adds a named startup task that must be completed with CompleteStartupTask
+// before the service is reported as started.
+func (h *Health) AddStartupTask(name string) error {
+	if name == "" {
+		return errors.New("startup task must have a name to be added")
+	}
+
+	h.startup.mu.Lock()
+	defer h.startup.mu.Unlock()
+
+	if h.startup.started {
+		return fmt.Errorf("startup task %q is added after the service has started", name)
+	}
+
+	if _, ok := h.startup.tasks[name]; ok {
+		return fmt.Errorf("startup task %q is already added", name)
+	}
+
+	h.startup.tasks[name] = false
+
+	return nil
+}
+
+// CompleteStartupTask marks the named startup task as completed.
+func (h *Health) CompleteStartupTask(name string) error {
+	h.startup.mu.Lock()
+	defer h.startup.mu.Unlock()
+
+	if _, ok := h.startup.tasks[name]; !ok {
+		return fmt.Errorf("startup task %q is not added", name)
+	}
+
+	h.startup.tasks[name] = true
+
+	return nil
+}
+
+// Started tells if the service has started, i.e. all the startup tasks were completed
+// and all the checks tagged with TagStartup passed at once.
+func (h *Health) Started() bool {
+	h.startup.mu.Lock()
+	defer h.startup.mu.Unlock()
+
+	return h.startup.started
+}
+
+// MeasureStartup runs the checks tagged with TagStartup until they all pass at once and all the startup tasks
+// are completed. From then on the service is reported as started and the checks are not run anymore.
+func (h *Health) MeasureStartup(ctx context.Context) Startup {
+	if h.Started() {
+		return Startup{IsOK: true, Status: StatusOK}
+	}
+
+	checks := h.tagged([]string{TagStartup})
+	c := h.measure(ctx, checks)
+
+	h.startup.mu.Lock()
+	defer h.startup.mu.Unlock()
+
+	var pending []string
+	for name, completed := range h.startup.tasks {
+		if !completed {
+			pending = append(pending, name)
+		}
+	}
+	sort.Strings(pending)
+
+	// every startup check must have passed, the checks that have no result yet have not
+	started := len(pending) == 0
+	for _, rc := range checks {
+		if rc.config.Criticality == CriticalityInformational {
+			continue
+		}
+
+		if s, ok := c.Services[rc.config.Name]; !ok || !startupPassed(s) {
+			started = false
+		}
+	}
+
+	if started || h.startup.started {
+		h.startup.started = true
+		return Startup{IsOK: true, Status: StatusOK}
+	}
+
+	return Startup{
+		IsOK:         false,
+		Status:       StatusUnavailable,
+		Services:     c.Services,
+		PendingTasks: pending,
+	}
+}
+
+// startupPassed tells if the check run passed, regardless of the check thresholds:
+// a failure below Config.FailureThreshold is reported as ok, but it does not complete the startup.
+func startupPassed(s ServiceStatus) bool {
+	return s.IsOk && !s.Failed() && !s.Skipped && !s.ShortCircuited
+}
+
+// StartupHandler returns a startup HTTP handler (http.HandlerFunc).
+func (h *Health) StartupHandler(opts ...HandlerOption) http.Handler {
+	hc := newHandlerConfig(opts)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := h.MeasureStartup(r.Context())
+
+		h.writeJSON(w, contentTypeJSON, s, s.Status, hc)
+	})
+}
+
+// StartupHandlerFunc is the startup HTTP handler function, see MeasureStartup.
+func (h *Health) StartupHandlerFunc(w http.ResponseWriter, r *http.Request) {
+	s := h.MeasureStartup(r.Context())
+
+	h.writeJSON(w, contentTypeJSON, s, s.Status, handlerConfig{})
+}
@@ -0,0 +1,138 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartupHandler(t *testing.T) {
+	var (
+		calls int32
+		fail  int32 = 1
+	)
+
+	h, err := New(WithStartupTasks("migrations", "cache"), WithChecks(Config{
+		Name: "kafka-consumer",
+		Tags: []string{TagStartup},
+		Check: func(context.Context) error {
+			atomic.AddInt32(&calls, 1)
+			if atomic.LoadInt32(&fail) == 1 {
+				return errors.New(checkErr)
+			}
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	probe := func() (int, Startup) {
+		res := httptest.NewRecorder()
+		h.StartupHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/startup", nil))
+
+		var s Startup
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&s))
+
+		return res.Code, s
+	}
+
+	code, s := probe()
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Equal(t, StatusUnavailable, s.Status)
+	assert.Equal(t, []string{"cache", "migrations"}, s.PendingTasks)
+	assert.Equal(t, checkErr, s.Services["kafka-consumer"].Message)
+
+	require.NoError(t, h.CompleteStartupTask("migrations"))
+	require.NoError(t, h.CompleteStartupTask("cache"))
+	require.Error(t, h.CompleteStartupTask("unknown"))
+
+	code, s = probe()
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Empty(t, s.PendingTasks)
+	assert.False(t, h.Started())
+
+	atomic.StoreInt32(&fail, 0)
+
+	code, s = probe()
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, bool(s.IsOK))
+	assert.True(t, h.Started())
+
+	// the startup is latched, the checks are not run anymore
+	atomic.StoreInt32(&fail, 1)
+	before := atomic.LoadInt32(&calls)
+
+	code, s = probe()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusOK, s.Status)
+	assert.Equal(t, before, atomic.LoadInt32(&calls))
+
+	require.Error(t, h.AddStartupTask("late"), "adding a task after the service has started should return an error")
+}
+
+func TestAddStartupTask(t *testing.T) {
+	h, err := New()
+	require.NoError(t, err)
+
+	require.Error(t, h.AddStartupTask(""))
+	require.NoError(t, h.AddStartupTask("warmup"))
+	require.Error(t, h.AddStartupTask("warmup"))
+
+	_, err = New(WithStartupTasks("warmup", "warmup"))
+	require.Error(t, err)
+}
+
+func TestStartupFailureThreshold(t *testing.T) {
+	var fail int32 = 1
+
+	h, err := New(WithChecks(Config{
+		Name:             "migrations",
+		Tags:             []string{TagStartup},
+		FailureThreshold: 3,
+		Check: func(context.Context) error {
+			if atomic.LoadInt32(&fail) == 1 {
+				return errors.New("migrations pending")
+			}
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	s := h.MeasureStartup(context.Background())
+	assert.False(t, bool(s.IsOK), "failure below the threshold should not complete the startup")
+	assert.True(t, s.Services["migrations"].IsOk)
+	assert.False(t, h.Started())
+
+	atomic.StoreInt32(&fail, 0)
+
+	s = h.MeasureStartup(context.Background())
+	assert.True(t, bool(s.IsOK))
+	assert.True(t, h.Started())
+}
+
+func TestStartupBackgroundNotRunYet(t *testing.T) {
+	h, err := New(
+		WithBackgroundInterval(time.Hour),
+		WithBackgroundJitter(time.Hour),
+		WithChecks(Config{
+			Name:  "migrations",
+			Tags:  []string{TagStartup},
+			Check: func(context.Context) error { return nil },
+		}),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, h.Start(context.Background()))
+	defer h.Stop()
+
+	s := h.MeasureStartup(context.Background())
+	assert.False(t, bool(s.IsOK), "startup check that has not run yet should not complete the startup")
+	assert.False(t, h.Started())
+}
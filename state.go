@@ -0,0 +1,136 @@
+package health
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// checkState keeps the outcome of the previous runs of a check between measurements.
+type checkState struct {
+	mu          sync.Mutex
+	lastSuccess time.Time
+	lastFailure time.Time
+	failures    int
+	successes   int
+	// healthy is the reported state of the check, it changes only when a threshold is reached.
+	healthy bool
+	// last is the latest reported result, nil if the check has not run yet.
+	last *ServiceStatus
+	// status is the latest reported status and changedAt is the time in which it changed.
+	status    Status
+	changedAt time.Time
+	breaker   breaker
+}
+
+func newCheckState() *checkState {
+	return &checkState{healthy: true, status: StatusOK}
+}
+
+// record updates the state with the result of a check run and fills the result with the state details.
+// The result is reported as ok or failed according to the check thresholds.
+// The status change event is returned if the reported status of the check has changed.
+func (st *checkState) record(c Config, res *ServiceStatus) *Event {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	finishedAt := res.StartedAt.Add(res.Duration)
+
+	// the skipped and the short-circuited runs are reported as failed, but they do not count towards the thresholds
+	if !res.Skipped && !res.ShortCircuited {
+		st.count(c, res, finishedAt)
+	}
+
+	st.trip(c, res, finishedAt)
+
+	res.ConsecutiveFailures = st.failures
+	res.ConsecutiveSuccesses = st.successes
+
+	if !st.lastSuccess.IsZero() {
+		lastSuccess := st.lastSuccess
+		res.LastSuccess = &lastSuccess
+	}
+
+	if !st.lastFailure.IsZero() {
+		lastFailure := st.lastFailure
+		res.LastFailure = &lastFailure
+	}
+
+	last := *res
+	st.last = &last
+
+	status := checkStatus(*res)
+	if status == st.status {
+		return nil
+	}
+
+	e := &Event{
+		Name:      c.Name,
+		Previous:  st.status,
+		Current:   status,
+		Message:   res.Message,
+		Timestamp: finishedAt,
+		Since:     st.changedAt,
+	}
+
+	st.status = status
+	st.changedAt = finishedAt
+
+	return e
+}
+
+// count updates the streaks with the check run and applies the check thresholds to the result.
+func (st *checkState) count(c Config, res *ServiceStatus, finishedAt time.Time) {
+	if res.IsOk {
+		st.lastSuccess = finishedAt
+		st.successes++
+		st.failures = 0
+	} else {
+		st.lastFailure = finishedAt
+		st.failures++
+		st.successes = 0
+	}
+
+	switch {
+	case st.healthy && st.failures >= c.FailureThreshold:
+		st.healthy = false
+	case !st.healthy && st.successes >= c.SuccessThreshold:
+		st.healthy = true
+	}
+
+	if res.IsOk && !st.healthy {
+		res.IsOk = false
+		res.Message = fmt.Sprintf("health check is recovering: %d of %d consecutive successes", st.successes, c.SuccessThreshold)
+	} else if !res.IsOk && st.healthy {
+		// the failure is below the threshold, the check is still reported as ok along with the error message
+		res.IsOk = true
+	}
+}
+
+// checkStatus returns the status of a single check.
+func checkStatus(s ServiceStatus) Status {
+	switch {
+	case s.IsOk && s.Warning != "":
+		return StatusPartiallyAvailable
+	case s.IsOk:
+		return StatusOK
+	case s.TimedOut:
+		return StatusTimeout
+	case s.Skippable:
+		return StatusPartiallyAvailable
+	default:
+		return StatusUnavailable
+	}
+}
+
+// lastResult returns the latest reported result of the check.
+func (st *checkState) lastResult() (ServiceStatus, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if st.last == nil {
+		return ServiceStatus{}, false
+	}
+
+	return *st.last, true
+}
@@ -0,0 +1,83 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckStateThresholds(t *testing.T) {
+	c := Config{Name: "redis", FailureThreshold: 3, SuccessThreshold: 2}
+	st := newCheckState()
+
+	run := func(ok bool) ServiceStatus {
+		res := ServiceStatus{IsOk: ok, StartedAt: time.Now()}
+		if !ok {
+			res.Message = checkErr
+		}
+		st.record(c, &res)
+		return res
+	}
+
+	res := run(false)
+	assert.True(t, res.IsOk, "failure below the threshold should be reported as ok")
+	assert.Equal(t, checkErr, res.Message)
+	assert.Equal(t, 1, res.ConsecutiveFailures)
+
+	res = run(false)
+	assert.True(t, res.IsOk)
+	assert.Equal(t, 2, res.ConsecutiveFailures)
+
+	res = run(false)
+	assert.False(t, res.IsOk, "failure reaching the threshold should be reported as failed")
+	assert.Equal(t, 3, res.ConsecutiveFailures)
+
+	res = run(true)
+	assert.False(t, res.IsOk, "success below the threshold should be reported as failed")
+	assert.Equal(t, 1, res.ConsecutiveSuccesses)
+	assert.Equal(t, 0, res.ConsecutiveFailures)
+
+	res = run(true)
+	assert.True(t, res.IsOk, "success reaching the threshold should be reported as ok")
+	assert.Equal(t, 2, res.ConsecutiveSuccesses)
+
+	res = run(false)
+	assert.True(t, res.IsOk, "streak should be reset by a success")
+	assert.Equal(t, 1, res.ConsecutiveFailures)
+}
+
+func TestMeasureWithThresholds(t *testing.T) {
+	fail := true
+
+	h, err := New(WithChecks(Config{
+		Name:             "redis",
+		FailureThreshold: 2,
+		Check: func(context.Context) error {
+			if fail {
+				return errors.New(checkErr)
+			}
+			return nil
+		},
+	}))
+	require.NoError(t, err)
+
+	c := h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status)
+	assert.Equal(t, 1, c.Services["redis"].ConsecutiveFailures)
+
+	c = h.Measure(context.Background())
+	assert.Equal(t, StatusUnavailable, c.Status)
+	assert.Equal(t, 2, c.Services["redis"].ConsecutiveFailures)
+
+	fail = false
+	c = h.Measure(context.Background())
+	assert.Equal(t, StatusOK, c.Status)
+	assert.Equal(t, 1, c.Services["redis"].ConsecutiveSuccesses)
+
+	err = h.Register(Config{Name: "negative", FailureThreshold: -1})
+	require.Error(t, err)
+}
@@ -0,0 +1,175 @@
+package health
+
+import (
+	"os"
+	"runtime"
+	"time"
+)
+
+// SystemMetrics are the groups of the system metrics reported in the check response, see WithSystemMetrics.
+type SystemMetrics int
+
+const (
+	// SystemGC reports the garbage collector statistics.
+	SystemGC SystemMetrics = 1 << iota
+	// SystemMemory reports the heap in use along with the cgroup memory limit and usage.
+	SystemMemory
+	// SystemCPU reports the process CPU time along with the available CPUs and the cgroup CPU quota.
+	SystemCPU
+	// SystemFileDescriptors reports the open file descriptors along with their limit (RLIMIT_NOFILE).
+	SystemFileDescriptors
+	// SystemProcess reports the process ID, the host name, the process start time and uptime.
+	SystemProcess
+
+	// SystemAll reports all the groups of the system metrics.
+	SystemAll = SystemGC | SystemMemory | SystemCPU | SystemFileDescriptors | SystemProcess
+)
+
+type (
+	// GCStats are the garbage collector statistics.
+	GCStats struct {
+		// Count is the number of the completed GC cycles.
+		Count int `json:"count"`
+		// PauseTotalNs is the cumulative GC stop-the-world pause time in nanoseconds.
+		PauseTotalNs int64 `json:"pause_total_ns"`
+		// LastPauseNs is the stop-the-world pause time of the latest GC cycle in nanoseconds.
+		LastPauseNs int64 `json:"last_pause_ns"`
+		// LastGC is the time in which the latest GC cycle finished, it is not set if no cycle has completed.
+		LastGC *time.Time `json:"last_gc,omitempty"`
+		// CPUFraction is the fraction of the available CPU time used by the GC since the process started.
+		CPUFraction float64 `json:"cpu_fraction"`
+	}
+
+	// MemoryStats are the memory statistics of the process.
+	MemoryStats struct {
+		// HeapInUseBytes is the bytes in the in-use heap spans.
+		HeapInUseBytes int64 `json:"heap_in_use_bytes"`
+		// SysBytes is the total bytes of memory obtained from the OS.
+		SysBytes int64 `json:"sys_bytes"`
+		// LimitBytes is the cgroup memory limit, it is not set if there is no limit or it is not known.
+		LimitBytes int64 `json:"limit_bytes,omitempty"`
+		// UsageBytes is the cgroup memory usage, it is not set if it is not known.
+		UsageBytes int64 `json:"usage_bytes,omitempty"`
+	}
+
+	// CPUStats are the CPU statistics of the process.
+	CPUStats struct {
+		// UserSeconds is the CPU time spent by the process in the user mode.
+		UserSeconds float64 `json:"user_seconds,omitempty"`
+		// SystemSeconds is the CPU time spent by the process in the kernel mode.
+		SystemSeconds float64 `json:"system_seconds,omitempty"`
+		// NumCPU is the number of the logical CPUs of the host.
+		NumCPU int `json:"num_cpu"`
+		// GoMaxProcs is the maximum number of the CPUs executing Go code simultaneously.
+		GoMaxProcs int `json:"gomaxprocs"`
+		// QuotaCores is the cgroup CPU quota in cores, it is not set if there is no quota or it is not known.
+		QuotaCores float64 `json:"quota_cores,omitempty"`
+	}
+
+	// FileDescriptorStats are the file descriptors statistics of the process.
+	FileDescriptorStats struct {
+		// Open is the number of the open file descriptors.
+		Open int `json:"open"`
+		// Limit is the maximum number of the open file descriptors (RLIMIT_NOFILE).
+		Limit uint64 `json:"limit"`
+	}
+
+	// ProcessStats are the process details.
+	ProcessStats struct {
+		// PID is the process ID.
+		PID int `json:"pid"`
+		// Hostname is the host name, it is not set if it is not known.
+		Hostname string `json:"hostname,omitempty"`
+		// StartedAt is the time in which the process started.
+		StartedAt time.Time `json:"started_at"`
+		// UptimeSeconds is the time elapsed since the process started.
+		UptimeSeconds float64 `json:"uptime_seconds"`
+	}
+)
+
+// processStartedAt is the fallback process start time, if it is not known from the OS.
+var processStartedAt = time.Now()
+
+func newSystemMetrics(groups SystemMetrics) System {
+	s := runtime.MemStats{}
+	runtime.ReadMemStats(&s)
+
+	sys := System{
+		Version:          runtime.Version(),
+		GoroutinesCount:  runtime.NumGoroutine(),
+		TotalAllocBytes:  int(s.TotalAlloc),
+		HeapObjectsCount: int(s.HeapObjects),
+		AllocBytes:       int(s.Alloc),
+	}
+
+	if groups&SystemGC != 0 {
+		sys.GC = newGCStats(s)
+	}
+
+	if groups&SystemMemory != 0 {
+		sys.Memory = &MemoryStats{
+			HeapInUseBytes: int64(s.HeapInuse),
+			SysBytes:       int64(s.Sys),
+		}
+		sys.Memory.LimitBytes, sys.Memory.UsageBytes = cgroupMemory()
+	}
+
+	if groups&SystemCPU != 0 {
+		sys.CPU = &CPUStats{
+			NumCPU:     runtime.NumCPU(),
+			GoMaxProcs: runtime.GOMAXPROCS(0),
+			QuotaCores: cgroupCPUQuota(),
+		}
+
+		if user, system, ok := cpuTime(); ok {
+			sys.CPU.UserSeconds = user.Seconds()
+			sys.CPU.SystemSeconds = system.Seconds()
+		}
+	}
+
+	if groups&SystemFileDescriptors != 0 {
+		if open, limit, ok := fileDescriptors(); ok {
+			sys.FileDescriptors = &FileDescriptorStats{Open: open, Limit: limit}
+		}
+	}
+
+	if groups&SystemProcess != 0 {
+		sys.Process = newProcessStats()
+	}
+
+	return sys
+}
+
+func newGCStats(s runtime.MemStats) *GCStats {
+	gc := &GCStats{
+		Count:        int(s.NumGC),
+		PauseTotalNs: int64(s.PauseTotalNs),
+		CPUFraction:  s.GCCPUFraction,
+	}
+
+	if s.NumGC > 0 {
+		// the pauses are kept in a circular buffer, the latest one is at (NumGC+255)%256
+		gc.LastPauseNs = int64(s.PauseNs[(s.NumGC+255)%256])
+
+		lastGC := time.Unix(0, int64(s.LastGC))
+		gc.LastGC = &lastGC
+	}
+
+	return gc
+}
+
+func newProcessStats() *ProcessStats {
+	startedAt, ok := processStartTime()
+	if !ok {
+		startedAt = processStartedAt
+	}
+
+	hostname, _ := os.Hostname()
+
+	return &ProcessStats{
+		PID:           os.Getpid(),
+		Hostname:      hostname,
+		StartedAt:     startedAt,
+		UptimeSeconds: time.Since(startedAt).Seconds(),
+	}
+}
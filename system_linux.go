@@ -0,0 +1,188 @@
+//go:build linux
+// +build linux
+
+package health
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// The paths of the proc and the cgroup file systems, they are variables to be replaced in the tests.
+var (
+	procRoot   = "/proc"
+	cgroupRoot = "/sys/fs/cgroup"
+)
+
+// unlimited is the threshold above which a cgroup v1 limit means no limit.
+const unlimited = 1 << 62
+
+// clockTicks is the USER_HZ the process start time is measured in, it is 100 on all the supported architectures.
+const clockTicks = 100
+
+// cgroupMemory returns the cgroup memory limit and usage, the values that are not known are zero.
+func cgroupMemory() (limit, usage int64) {
+	// cgroup v2, the limit is "max" if there is no limit
+	if data, err := os.ReadFile(filepath.Join(cgroupRoot, "memory.max")); err == nil {
+		limit, _ = parseInt(data)
+		usage, _ = readInt(filepath.Join(cgroupRoot, "memory.current"))
+
+		return limit, usage
+	}
+
+	// cgroup v1
+	if v, ok := readInt(filepath.Join(cgroupRoot, "memory", "memory.limit_in_bytes")); ok && v < unlimited {
+		limit = v
+	}
+	usage, _ = readInt(filepath.Join(cgroupRoot, "memory", "memory.usage_in_bytes"))
+
+	return limit, usage
+}
+
+// cgroupCPUQuota returns the cgroup CPU quota in cores, zero if there is no quota or it is not known.
+func cgroupCPUQuota() float64 {
+	// cgroup v2, "$MAX $PERIOD" or "max $PERIOD"
+	if data, err := os.ReadFile(filepath.Join(cgroupRoot, "cpu.max")); err == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) != 2 {
+			return 0
+		}
+
+		return quota(fields[0], fields[1])
+	}
+
+	// cgroup v1, the quota is -1 if there is no quota
+	q, err := os.ReadFile(filepath.Join(cgroupRoot, "cpu", "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0
+	}
+
+	p, err := os.ReadFile(filepath.Join(cgroupRoot, "cpu", "cpu.cfs_period_us"))
+	if err != nil {
+		return 0
+	}
+
+	return quota(strings.TrimSpace(string(q)), strings.TrimSpace(string(p)))
+}
+
+func quota(max, period string) float64 {
+	m, err := strconv.ParseFloat(max, 64)
+	if err != nil || m <= 0 {
+		return 0
+	}
+
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+
+	return m / p
+}
+
+// cpuTime returns the CPU time spent by the process in the user and the kernel modes.
+func cpuTime() (user, system time.Duration, ok bool) {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0, 0, false
+	}
+
+	return time.Duration(ru.Utime.Nano()), time.Duration(ru.Stime.Nano()), true
+}
+
+// fileDescriptors returns the number of the open file descriptors and their limit.
+func fileDescriptors() (open int, limit uint64, ok bool) {
+	var rl syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rl); err != nil {
+		return 0, 0, false
+	}
+
+	dir, err := os.Open(filepath.Join(procRoot, "self", "fd"))
+	if err != nil {
+		return 0, 0, false
+	}
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	// the descriptor opened to read the directory is listed as well, it is not counted
+	self := strconv.FormatUint(uint64(dir.Fd()), 10)
+	for _, name := range names {
+		if name != self {
+			open++
+		}
+	}
+
+	return open, rl.Cur, true
+}
+
+// processStartTime returns the process start time, computed from the system boot time and the process start
+// time in clock ticks since boot.
+func processStartTime() (time.Time, bool) {
+	stat, err := os.ReadFile(filepath.Join(procRoot, "self", "stat"))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	// the process name may contain spaces, the fields are counted after its closing parenthesis
+	i := strings.LastIndexByte(string(stat), ')')
+	if i < 0 {
+		return time.Time{}, false
+	}
+
+	// starttime is the 22nd field, the fields after the name start from the 3rd one
+	fields := strings.Fields(string(stat[i+1:]))
+	if len(fields) < 20 {
+		return time.Time{}, false
+	}
+
+	ticks, err := strconv.ParseInt(fields[19], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(procRoot, "stat"))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "btime ") {
+			continue
+		}
+
+		bootTime, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(line, "btime ")), 10, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+
+		return time.Unix(bootTime, 0).Add(time.Duration(ticks) * time.Second / clockTicks), true
+	}
+
+	return time.Time{}, false
+}
+
+// readInt reads the integer value of a file, ok is false if the file is missing or does not hold an integer.
+func readInt(path string) (int64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+
+	return parseInt(data)
+}
+
+func parseInt(data []byte) (int64, bool) {
+	v, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return v, true
+}
@@ -0,0 +1,134 @@
+package health
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func withRoots(t *testing.T, proc, cgroup string) {
+	t.Helper()
+
+	prevProc, prevCgroup := procRoot, cgroupRoot
+	procRoot, cgroupRoot = proc, cgroup
+
+	t.Cleanup(func() {
+		procRoot, cgroupRoot = prevProc, prevCgroup
+	})
+}
+
+func TestCgroupV2(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"memory.max":     "536870912\n",
+		"memory.current": "104857600\n",
+		"cpu.max":        "150000 100000\n",
+	})
+	withRoots(t, root, root)
+
+	limit, usage := cgroupMemory()
+	assert.Equal(t, int64(536870912), limit)
+	assert.Equal(t, int64(104857600), usage)
+	assert.Equal(t, 1.5, cgroupCPUQuota())
+
+	writeFiles(t, root, map[string]string{
+		"memory.max": "max\n",
+		"cpu.max":    "max 100000\n",
+	})
+
+	limit, usage = cgroupMemory()
+	assert.Equal(t, int64(0), limit, "no limit should not be reported")
+	assert.Equal(t, int64(104857600), usage)
+	assert.Equal(t, 0.0, cgroupCPUQuota())
+}
+
+func TestCgroupV1(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"memory/memory.limit_in_bytes": "9223372036854771712\n",
+		"memory/memory.usage_in_bytes": "2048\n",
+		"cpu/cpu.cfs_quota_us":         "-1\n",
+		"cpu/cpu.cfs_period_us":        "100000\n",
+	})
+	withRoots(t, root, root)
+
+	limit, usage := cgroupMemory()
+	assert.Equal(t, int64(0), limit, "no limit should not be reported")
+	assert.Equal(t, int64(2048), usage)
+	assert.Equal(t, 0.0, cgroupCPUQuota())
+
+	writeFiles(t, root, map[string]string{
+		"memory/memory.limit_in_bytes": "1073741824\n",
+		"cpu/cpu.cfs_quota_us":         "50000\n",
+	})
+
+	limit, _ = cgroupMemory()
+	assert.Equal(t, int64(1073741824), limit)
+	assert.Equal(t, 0.5, cgroupCPUQuota())
+}
+
+func TestSystemMetricsFallback(t *testing.T) {
+	root := t.TempDir()
+	withRoots(t, root, root)
+
+	limit, usage := cgroupMemory()
+	assert.Zero(t, limit)
+	assert.Zero(t, usage)
+	assert.Zero(t, cgroupCPUQuota())
+
+	_, _, ok := fileDescriptors()
+	assert.False(t, ok)
+
+	_, ok = processStartTime()
+	assert.False(t, ok)
+
+	s := newSystemMetrics(SystemAll)
+	assert.Nil(t, s.FileDescriptors)
+	assert.Equal(t, processStartedAt, s.Process.StartedAt)
+}
+
+func TestProcessStartTime(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"self/stat": "42 (health test) S 1 42 42 0 -1 4194560 100 0 0 0 1 2 0 0 20 0 8 0 12345 0 0\n",
+		"stat":      "cpu  1 2 3 4\nbtime 1600000000\nprocesses 100\n",
+	})
+	withRoots(t, root, root)
+
+	startedAt, ok := processStartTime()
+	require.True(t, ok)
+	assert.Equal(t, time.Unix(1600000000, 0).Add(123450*time.Millisecond), startedAt)
+}
+
+func TestFileDescriptors(t *testing.T) {
+	open, limit, ok := fileDescriptors()
+	require.True(t, ok)
+	assert.Greater(t, open, 0)
+	assert.Greater(t, limit, uint64(0))
+
+	// the descriptors in use are the ones that can be stat-ed
+	var inUse int
+	var st syscall.Stat_t
+	for fd := 0; fd < 1024; fd++ {
+		if syscall.Fstat(fd, &st) == nil {
+			inUse++
+		}
+	}
+
+	assert.Equal(t, inUse, open, "the descriptor opened to read the directory should not be counted")
+}
@@ -0,0 +1,32 @@
+//go:build !linux
+// +build !linux
+
+package health
+
+import "time"
+
+// cgroupMemory returns the cgroup memory limit and usage, they are not known on this platform.
+func cgroupMemory() (limit, usage int64) {
+	return 0, 0
+}
+
+// cgroupCPUQuota returns the cgroup CPU quota in cores, it is not known on this platform.
+func cgroupCPUQuota() float64 {
+	return 0
+}
+
+// cpuTime returns the CPU time spent by the process, it is not known on this platform.
+func cpuTime() (user, system time.Duration, ok bool) {
+	return 0, 0, false
+}
+
+// fileDescriptors returns the number of the open file descriptors and their limit,
+// they are not known on this platform.
+func fileDescriptors() (open int, limit uint64, ok bool) {
+	return 0, 0, false
+}
+
+// processStartTime returns the process start time, it is not known on this platform.
+func processStartTime() (time.Time, bool) {
+	return time.Time{}, false
+}
@@ -0,0 +1,59 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemMetrics(t *testing.T) {
+	runtime.GC()
+
+	h, err := New(WithSystemMetrics(SystemAll))
+	require.NoError(t, err)
+
+	s := h.Measure(context.Background()).System
+	require.NotNil(t, s.GC)
+	assert.Greater(t, s.GC.Count, 0)
+	assert.NotNil(t, s.GC.LastGC)
+
+	require.NotNil(t, s.Memory)
+	assert.Greater(t, s.Memory.HeapInUseBytes, int64(0))
+
+	require.NotNil(t, s.CPU)
+	assert.Equal(t, runtime.NumCPU(), s.CPU.NumCPU)
+
+	require.NotNil(t, s.Process)
+	assert.Greater(t, s.Process.PID, 0)
+	assert.False(t, s.Process.StartedAt.IsZero())
+	assert.GreaterOrEqual(t, s.Process.UptimeSeconds, 0.0)
+}
+
+func TestWithSystemMetrics(t *testing.T) {
+	_, err := New(WithSystemMetrics(SystemAll + 1))
+	require.Error(t, err)
+
+	h, err := New(WithSystemMetrics(SystemGC | SystemProcess))
+	require.NoError(t, err)
+
+	s := h.Measure(context.Background()).System
+	assert.NotNil(t, s.GC)
+	assert.NotNil(t, s.Process)
+	assert.Nil(t, s.Memory)
+	assert.Nil(t, s.CPU)
+	assert.Nil(t, s.FileDescriptors)
+
+	h, err = New()
+	require.NoError(t, err)
+
+	data, err := json.Marshal(h.Measure(context.Background()).System)
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &fields))
+	assert.Len(t, fields, 5, "only the basic system metrics should be reported by default")
+}
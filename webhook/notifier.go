@@ -0,0 +1,262 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/mhfinans/health-go"
+)
+
+const (
+	// SignatureHeader is the header carrying the HMAC-SHA256 signature of the payload, as "sha256={hex}".
+	SignatureHeader = "X-Health-Signature"
+
+	defaultContentType    = "application/json"
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultRequestTimeout = 10 * time.Second
+)
+
+// Config is the webhook notifier configuration settings container.
+type Config struct {
+	// URLs are the webhooks URLs the payload is POSTed to. Required.
+	URLs []string
+	// Template is the payload template, it is executed with the health.Event.
+	// If not set - the event is encoded as JSON
+	Template *template.Template
+	// ContentType is the payload content type.
+	// If not set - application/json
+	ContentType string
+	// Secret is the key the payload is signed with using HMAC-SHA256, the signature is sent in SignatureHeader.
+	// If not set - the payload is not signed
+	Secret []byte
+	// MaxAttempts is the maximum number of delivery attempts to every URL.
+	// If not set - 3
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, it is doubled for every next retry.
+	// If not set - 500 milliseconds
+	InitialBackoff time.Duration
+	// MinInterval is the minimum interval between two notifications about the same check or about the overall status.
+	// The changes happening in between are coalesced and only the latest one is sent at the end of the interval.
+	// If not set - every change is sent immediately
+	MinInterval time.Duration
+	// Client is the HTTP client the payload is sent with.
+	// If not set - the client with 10 seconds timeout
+	Client *http.Client
+	// Logger is the logger for the delivery failures.
+	// If not set - the failures are not logged
+	Logger health.Logger
+}
+
+// Notifier POSTs the health status changes to the webhooks, see New.
+type Notifier struct {
+	config Config
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	closed  bool
+	last    map[string]time.Time
+	pending map[string]*health.Event
+}
+
+// New creates new webhook notifier. Its Notify method is meant to be subscribed to the status changes:
+//
+//	h.Subscribe(notifier.Notify)
+func New(config Config) (*Notifier, error) {
+	if len(config.URLs) == 0 {
+		return nil, errors.New("webhook notifier must have at least one URL")
+	}
+
+	if config.ContentType == "" {
+		config.ContentType = defaultContentType
+	}
+
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+
+	if config.InitialBackoff == 0 {
+		config.InitialBackoff = defaultInitialBackoff
+	}
+
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: defaultRequestTimeout}
+	}
+
+	if config.Logger == nil {
+		config.Logger = health.NewNopLogger()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Notifier{
+		config:  config,
+		ctx:     ctx,
+		cancel:  cancel,
+		last:    make(map[string]time.Time),
+		pending: make(map[string]*health.Event),
+	}, nil
+}
+
+// Notify sends the event to the webhooks in background, respecting the minimum interval between notifications.
+func (n *Notifier) Notify(e health.Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.closed {
+		return
+	}
+
+	if n.pending[e.Name] != nil {
+		// the notification is already scheduled, it will carry the latest event
+		n.pending[e.Name] = &e
+		return
+	}
+
+	wait := n.config.MinInterval - time.Since(n.last[e.Name])
+	if _, ok := n.last[e.Name]; !ok || wait <= 0 {
+		n.last[e.Name] = time.Now()
+		n.send(e)
+		return
+	}
+
+	n.pending[e.Name] = &e
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		if pending := n.pending[e.Name]; pending != nil && !n.closed {
+			delete(n.pending, e.Name)
+			n.last[e.Name] = time.Now()
+			n.send(*pending)
+		}
+	}()
+}
+
+// Close drops the notifications delayed by the minimum interval,
+// cancels the deliveries in progress and waits for them to return.
+func (n *Notifier) Close() {
+	n.mu.Lock()
+	n.closed = true
+	n.mu.Unlock()
+
+	n.cancel()
+	n.wg.Wait()
+}
+
+// send delivers the event to every URL in background, n.mu must be held.
+func (n *Notifier) send(e health.Event) {
+	body, err := n.payload(e)
+	if err != nil {
+		n.config.Logger.Error("could not render webhook payload", health.F("error", err.Error()))
+		return
+	}
+
+	for _, url := range n.config.URLs {
+		n.wg.Add(1)
+		go func(url string) {
+			defer n.wg.Done()
+
+			if err := n.deliver(url, body); err != nil {
+				n.config.Logger.Error("could not deliver webhook", health.F("url", url), health.F("error", err.Error()))
+			}
+		}(url)
+	}
+}
+
+func (n *Notifier) payload(e health.Event) ([]byte, error) {
+	if n.config.Template == nil {
+		return json.Marshal(e)
+	}
+
+	var buf bytes.Buffer
+	if err := n.config.Template.Execute(&buf, e); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// deliver POSTs the body to the URL, retrying with exponential backoff until the notifier is closed.
+func (n *Notifier) deliver(url string, body []byte) error {
+	backoff := n.config.InitialBackoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = n.post(url, body); err == nil {
+			return nil
+		}
+
+		if attempt >= n.config.MaxAttempts {
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-n.ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("giving up after %d attempts: %w", attempt, n.ctx.Err())
+		case <-timer.C:
+		}
+
+		backoff *= 2
+	}
+}
+
+func (n *Notifier) post(url string, body []byte) error {
+	req, err := http.NewRequestWithContext(n.ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating the webhook request failed: %w", err)
+	}
+
+	req.Header.Set("Content-Type", n.config.ContentType)
+	if len(n.config.Secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(n.config.Secret, body))
+	}
+
+	res, err := n.config.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("making the webhook request failed: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status code %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// Sign returns the HMAC-SHA256 signature of the payload, as it is sent in SignatureHeader.
+func Sign(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
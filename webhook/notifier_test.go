@@ -0,0 +1,177 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/mhfinans/health-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	mu       sync.Mutex
+	fails    int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+
+	if r.fails > 0 {
+		r.fails--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.requests)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Config{})
+	require.Error(t, err)
+}
+
+func TestNotify(t *testing.T) {
+	rec := &recorder{fails: 1}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	secret := []byte("secret")
+	n, err := New(Config{
+		URLs:           []string{srv.URL},
+		Secret:         secret,
+		InitialBackoff: time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer n.Close()
+
+	h, err := health.New(health.WithChecks(health.Config{
+		Name:  "postgres",
+		Check: func(context.Context) error { return errors.New("postgres is down") },
+	}))
+	require.NoError(t, err)
+
+	h.Subscribe(n.Notify)
+	h.Measure(context.Background())
+
+	// the check and the overall status changed, the first delivery is retried
+	require.Eventually(t, func() bool { return rec.count() == 3 }, time.Second, 5*time.Millisecond)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	var checkEvent *health.Event
+	for i, body := range rec.bodies {
+		assert.Equal(t, "application/json", rec.requests[i].Header.Get("Content-Type"))
+		assert.Equal(t, Sign(secret, body), rec.requests[i].Header.Get(SignatureHeader))
+
+		var e health.Event
+		require.NoError(t, json.Unmarshal(body, &e))
+		if e.Name == "postgres" {
+			checkEvent = &e
+		}
+	}
+
+	require.NotNil(t, checkEvent)
+	assert.Equal(t, health.StatusOK, checkEvent.Previous)
+	assert.Equal(t, health.StatusUnavailable, checkEvent.Current)
+	assert.Equal(t, "postgres is down", checkEvent.Message)
+}
+
+func TestNotifyGivesUp(t *testing.T) {
+	rec := &recorder{fails: 10}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n, err := New(Config{URLs: []string{srv.URL}, MaxAttempts: 2, InitialBackoff: time.Millisecond})
+	require.NoError(t, err)
+
+	n.Notify(health.Event{Name: "postgres", Current: health.StatusUnavailable})
+
+	require.Eventually(t, func() bool { return rec.count() == 2 }, time.Second, 5*time.Millisecond)
+	n.Close()
+
+	assert.Equal(t, 2, rec.count())
+}
+
+func TestCloseCancelsDeliveries(t *testing.T) {
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		received <- struct{}{}
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	n, err := New(Config{URLs: []string{srv.URL}, InitialBackoff: time.Hour})
+	require.NoError(t, err)
+
+	n.Notify(health.Event{Name: "postgres", Current: health.StatusUnavailable})
+	<-received
+
+	closed := make(chan struct{})
+	go func() {
+		n.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("close should cancel the deliveries in progress")
+	}
+}
+
+func TestNotifyMinInterval(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	n, err := New(Config{
+		URLs:        []string{srv.URL},
+		MinInterval: 100 * time.Millisecond,
+		Template:    template.Must(template.New("payload").Parse(`{"text":"{{.Name}} is {{.Current}}"}`)),
+	})
+	require.NoError(t, err)
+	defer n.Close()
+
+	n.Notify(health.Event{Name: "postgres", Current: health.StatusUnavailable})
+	n.Notify(health.Event{Name: "postgres", Current: health.StatusOK})
+	n.Notify(health.Event{Name: "postgres", Current: health.StatusTimeout})
+	n.Notify(health.Event{Name: "redis", Current: health.StatusUnavailable})
+
+	require.Eventually(t, func() bool { return rec.count() == 2 }, time.Second, 5*time.Millisecond)
+	require.Eventually(t, func() bool { return rec.count() == 3 }, time.Second, 5*time.Millisecond)
+
+	time.Sleep(150 * time.Millisecond)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	require.Len(t, rec.bodies, 3, "the changes within the interval should be coalesced")
+	assert.Equal(t, `{"text":"postgres is Timeout during health check"}`, string(rec.bodies[2]))
+}